@@ -0,0 +1,178 @@
+package airflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+// ErrNotFound is returned when the connection, variable or pool does not exist.
+var ErrNotFound = errors.New("airflow object not found")
+
+// Client talks to the stable REST API of Airflow 2.
+type Client struct {
+	airflowConfig configs.AirflowConfig
+	httpClient    *http.Client
+}
+
+type Connection struct {
+	ConnectionID string `json:"connection_id"`
+	ConnType     string `json:"conn_type"`
+	Host         string `json:"host,omitempty"`
+	Login        string `json:"login,omitempty"`
+	Schema       string `json:"schema,omitempty"`
+	Port         *int   `json:"port,omitempty"`
+	Password     string `json:"password,omitempty"`
+	Extra        string `json:"extra,omitempty"`
+}
+
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Pool struct {
+	Name          string `json:"name"`
+	Slots         int    `json:"slots"`
+	OccupiedSlots int    `json:"occupied_slots,omitempty"`
+	RunningSlots  int    `json:"running_slots,omitempty"`
+	QueuedSlots   int    `json:"queued_slots,omitempty"`
+	OpenSlots     int    `json:"open_slots,omitempty"`
+}
+
+var airflowClient *Client
+
+func GetAirflowClient(airflowConfig configs.AirflowConfig) *Client {
+	if airflowClient != nil {
+		return airflowClient
+	}
+	airflowClient = &Client{}
+	airflowClient.airflowConfig = airflowConfig
+	airflowClient.httpClient = &http.Client{
+		Timeout: airflowConfig.Timeout,
+	}
+	return airflowClient
+}
+
+// PutConnection creates the connection, or replaces every field of an
+// existing one.
+func (c *Client) PutConnection(ctx context.Context, connection Connection) error {
+	err := c.do(ctx, http.MethodPatch, "/connections/"+url.PathEscape(connection.ConnectionID), connection, nil)
+	if errors.Is(err, ErrNotFound) {
+		return c.do(ctx, http.MethodPost, "/connections", connection, nil)
+	}
+	return err
+}
+
+func (c *Client) GetConnection(ctx context.Context, id string) (Connection, error) {
+	connection := Connection{}
+	err := c.do(ctx, http.MethodGet, "/connections/"+url.PathEscape(id), nil, &connection)
+	return connection, err
+}
+
+func (c *Client) DeleteConnection(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/connections/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *Client) PutVariable(ctx context.Context, variable Variable) error {
+	err := c.do(ctx, http.MethodPatch, "/variables/"+url.PathEscape(variable.Key), variable, nil)
+	if errors.Is(err, ErrNotFound) {
+		return c.do(ctx, http.MethodPost, "/variables", variable, nil)
+	}
+	return err
+}
+
+func (c *Client) GetVariable(ctx context.Context, key string) (Variable, error) {
+	variable := Variable{}
+	err := c.do(ctx, http.MethodGet, "/variables/"+url.PathEscape(key), nil, &variable)
+	return variable, err
+}
+
+func (c *Client) DeleteVariable(ctx context.Context, key string) error {
+	return c.do(ctx, http.MethodDelete, "/variables/"+url.PathEscape(key), nil, nil)
+}
+
+func (c *Client) PutPool(ctx context.Context, pool Pool) error {
+	body := map[string]interface{}{
+		"name":  pool.Name,
+		"slots": pool.Slots,
+	}
+	err := c.do(ctx, http.MethodPatch, "/pools/"+url.PathEscape(pool.Name), body, nil)
+	if errors.Is(err, ErrNotFound) {
+		return c.do(ctx, http.MethodPost, "/pools", body, nil)
+	}
+	return err
+}
+
+func (c *Client) GetPool(ctx context.Context, name string) (Pool, error) {
+	pool := Pool{}
+	err := c.do(ctx, http.MethodGet, "/pools/"+url.PathEscape(name), nil, &pool)
+	return pool, err
+}
+
+func (c *Client) DeletePool(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/pools/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	if c.airflowConfig.URL == "" {
+		return errors.New("no airflow url configured")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.airflowConfig.URL, "/")+"/api/v1"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.airflowConfig.Username != "" {
+		req.SetBasicAuth(c.airflowConfig.Username, c.airflowConfig.Password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if res.StatusCode >= 300 {
+		// errors come as problem details {"title": string, "detail": string}
+		airflowError := struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		}{}
+		data, _ := ioutil.ReadAll(res.Body)
+		if json.Unmarshal(data, &airflowError) == nil && airflowError.Detail != "" {
+			return fmt.Errorf("airflow %s %s: %s", method, path, airflowError.Detail)
+		}
+		return fmt.Errorf("airflow %s %s: %s", method, path, res.Status)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,99 @@
+package awsconfig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+type configKey struct {
+	account string
+	region  string
+}
+
+var (
+	awsConfigs = map[configKey]aws.Config{}
+	baseConfig *aws.Config
+	mutex      sync.Mutex
+)
+
+// GetAWSConfig returns the AWS config for the given account profile and
+// region. An empty account falls back to the default account and an empty
+// region to the region of the account, configs are cached per pair.
+func GetAWSConfig(awsConfig configs.AWSConfig, account string, region string) (aws.Config, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if account == "" {
+		account = awsConfig.DefaultAccount
+	}
+
+	var profile configs.AWSAccount
+	if account != "" {
+		found := false
+		for _, candidate := range awsConfig.Accounts {
+			if candidate.Name == account {
+				profile = candidate
+				found = true
+				break
+			}
+		}
+		if !found {
+			return aws.Config{}, fmt.Errorf("aws account %s is not configured", account)
+		}
+	}
+	if region == "" {
+		region = profile.Region
+	}
+
+	key := configKey{account: account, region: region}
+	if cfg, ok := awsConfigs[key]; ok {
+		return cfg, nil
+	}
+
+	if baseConfig == nil {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return aws.Config{}, err
+		}
+		baseConfig = &cfg
+	}
+
+	cfg := baseConfig.Copy()
+	if region != "" {
+		cfg.Region = region
+	}
+	if awsConfig.Endpoint != "" {
+		cfg.EndpointResolverWithOptions = localEndpoint(awsConfig.Endpoint)
+	}
+	if profile.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), profile.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if profile.ExternalID != "" {
+				o.ExternalID = aws.String(profile.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	awsConfigs[key] = cfg
+	return cfg, nil
+}
+
+// localEndpoint sends every service to a single endpoint, such as a local AWS
+// stand-in used for testing.
+func localEndpoint(url string) aws.EndpointResolverWithOptions {
+	return aws.EndpointResolverWithOptionsFunc(func(service string, region string, options ...interface{}) (aws.Endpoint, error) {
+		endpoint := aws.Endpoint{
+			URL:               url,
+			HostnameImmutable: true,
+			SigningRegion:     region,
+		}
+		return endpoint, nil
+	})
+}
@@ -2,16 +2,30 @@ package configs
 
 import (
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type AppConfigs struct {
-	Server     ServerConfig `yaml:"server"`
-	Database   DBConfig     `yaml:"database"`
-	Kubernetes AuthConfig   `yaml:"kubernetes"`
-	Vault      VaultConfig  `yaml:"vault"`
-	ChartRepo  ChartRepo    `yaml:"chartRepo"`
+	Server         ServerConfig         `yaml:"server"`
+	Database       DBConfig             `yaml:"database"`
+	Kubernetes     AuthConfig           `yaml:"kubernetes"`
+	Vault          VaultConfig          `yaml:"vault"`
+	ChartRepo      ChartRepo            `yaml:"chartRepo"`
+	Helm           HelmConfig           `yaml:"helm"`
+	Kinesis        KinesisConfig        `yaml:"kinesis"`
+	AWS            AWSConfig            `yaml:"aws"`
+	Kafka          KafkaConfig          `yaml:"kafka"`
+	KafkaConnect   KafkaConnectConfig   `yaml:"kafkaConnect"`
+	SchemaRegistry SchemaRegistryConfig `yaml:"schemaRegistry"`
+	Postgres       PostgresConfig       `yaml:"postgres"`
+	DynamoDB       DynamoDBConfig       `yaml:"dynamodb"`
+	Webhook        WebhookConfig        `yaml:"webhook"`
+	Airflow        AirflowConfig        `yaml:"airflow"`
+	SecretsManager SecretsManagerConfig `yaml:"secretsManager"`
+	SSM            SSMConfig            `yaml:"ssm"`
+	SecretFile     SecretFileConfig     `yaml:"secretFile"`
 }
 
 type ServerConfig struct {
@@ -28,11 +42,16 @@ type DBConfig struct {
 }
 
 type AuthConfig struct {
-	Method             string   `yaml:"method" env:"KUBERNETES_AUTHENTICATION_METHOD" env-default:"kubeconfig"`
-	DefaultNamespace   string   `yaml:"defaultNamespace" env:"KUBERNETES_DEFAULT_NAMESPACE" env-devault:"default"`
-	AvailableNamespace []string `yaml:"availableNamespace" env:"KUBERNETES_AVAILABLE_NAMESPACE" env-devault:"default"`
+	Method             string        `yaml:"method" env:"KUBERNETES_AUTHENTICATION_METHOD" env-default:"kubeconfig"`
+	DefaultNamespace   string        `yaml:"defaultNamespace" env:"KUBERNETES_DEFAULT_NAMESPACE" env-devault:"default"`
+	AvailableNamespace []string      `yaml:"availableNamespace" env:"KUBERNETES_AVAILABLE_NAMESPACE" env-devault:"default"`
+	SecretSyncInterval time.Duration `yaml:"secretSyncInterval" env:"KUBERNETES_SECRET_SYNC_INTERVAL" env-default:"5m"`
+	SecretProvider     bool          `yaml:"secretProvider" env:"KUBERNETES_SECRET_PROVIDER_ENABLED" env-default:"false"`
+	ClusterKinds       []string      `yaml:"clusterKinds" env:"KUBERNETES_MANIFEST_CLUSTER_KINDS"`
 }
 
+// VaultConfig configures the vault secret provider, which is only used when
+// a url is set.
 type VaultConfig struct {
 	URL            string              `yaml:"url" env:"VAULT_URL"`
 	AuthMethod     string              `yaml:"authMethod" env:"VAULT_AUTH_METHOD"`
@@ -55,6 +74,109 @@ type ChartRepo struct {
 	URL  string `yaml:"url" env:"CHART_REPO_URL"`
 }
 
+type HelmConfig struct {
+	DefaultTimeout    time.Duration `yaml:"defaultTimeout" env:"HELM_DEFAULT_TIMEOUT" env-default:"5m"`
+	MaxTimeout        time.Duration `yaml:"maxTimeout" env:"HELM_MAX_TIMEOUT" env-default:"30m"`
+	DefaultWait       bool          `yaml:"defaultWait" env:"HELM_DEFAULT_WAIT" env-default:"true"`
+	DefaultMaxHistory int           `yaml:"defaultMaxHistory" env:"HELM_DEFAULT_MAX_HISTORY" env-default:"10"`
+	MaxHistoryLimit   int           `yaml:"maxHistoryLimit" env:"HELM_MAX_HISTORY_LIMIT" env-default:"100"`
+	AllowLocalCharts  bool          `yaml:"allowLocalCharts" env:"HELM_ALLOW_LOCAL_CHARTS" env-default:"false"`
+}
+
+type KinesisConfig struct {
+	WaitTimeout  time.Duration `yaml:"waitTimeout" env:"KINESIS_WAIT_TIMEOUT" env-default:"5m"`
+	PollInterval time.Duration `yaml:"pollInterval" env:"KINESIS_POLL_INTERVAL" env-default:"5s"`
+}
+
+type AWSConfig struct {
+	DefaultAccount string       `yaml:"defaultAccount" env:"AWS_DEFAULT_ACCOUNT"`
+	Endpoint       string       `yaml:"endpoint" env:"AWS_ENDPOINT"`
+	Accounts       []AWSAccount `yaml:"accounts"`
+}
+
+type KafkaConfig struct {
+	Brokers  []string      `yaml:"brokers" env:"KAFKA_BROKERS"`
+	TLS      bool          `yaml:"tls" env:"KAFKA_TLS" env-default:"false"`
+	Username string        `yaml:"username" env:"KAFKA_USERNAME"`
+	Password string        `yaml:"password" env:"KAFKA_PASSWORD"`
+	Timeout  time.Duration `yaml:"timeout" env:"KAFKA_TIMEOUT" env-default:"30s"`
+}
+
+type KafkaConnectConfig struct {
+	URL          string        `yaml:"url" env:"KAFKA_CONNECT_URL"`
+	Username     string        `yaml:"username" env:"KAFKA_CONNECT_USERNAME"`
+	Password     string        `yaml:"password" env:"KAFKA_CONNECT_PASSWORD"`
+	Timeout      time.Duration `yaml:"timeout" env:"KAFKA_CONNECT_TIMEOUT" env-default:"30s"`
+	WaitTimeout  time.Duration `yaml:"waitTimeout" env:"KAFKA_CONNECT_WAIT_TIMEOUT" env-default:"2m"`
+	PollInterval time.Duration `yaml:"pollInterval" env:"KAFKA_CONNECT_POLL_INTERVAL" env-default:"5s"`
+}
+
+type SchemaRegistryConfig struct {
+	URL      string        `yaml:"url" env:"SCHEMA_REGISTRY_URL"`
+	Username string        `yaml:"username" env:"SCHEMA_REGISTRY_USERNAME"`
+	Password string        `yaml:"password" env:"SCHEMA_REGISTRY_PASSWORD"`
+	Timeout  time.Duration `yaml:"timeout" env:"SCHEMA_REGISTRY_TIMEOUT" env-default:"30s"`
+}
+
+type PostgresConfig struct {
+	Host        string `yaml:"host" env:"POSTGRES_HOST"`
+	Port        int    `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	User        string `yaml:"user" env:"POSTGRES_USER"`
+	Password    string `yaml:"pass" env:"POSTGRES_PASS"`
+	Database    string `yaml:"database" env:"POSTGRES_DATABASE" env-default:"postgres"`
+	SSLMode     string `yaml:"sslMode" env:"POSTGRES_SSL_MODE" env-default:"prefer"`
+	VaultMount  string `yaml:"vaultMount" env:"POSTGRES_VAULT_MOUNT" env-default:"secret"`
+	VaultPrefix string `yaml:"vaultPrefix" env:"POSTGRES_VAULT_PREFIX" env-default:"warehouse-controller/postgres"`
+}
+
+type DynamoDBConfig struct {
+	WaitTimeout  time.Duration `yaml:"waitTimeout" env:"DYNAMODB_WAIT_TIMEOUT" env-default:"30m"`
+	PollInterval time.Duration `yaml:"pollInterval" env:"DYNAMODB_POLL_INTERVAL" env-default:"10s"`
+}
+
+type WebhookConfig struct {
+	Timeout       time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"30s"`
+	Retries       int           `yaml:"retries" env:"WEBHOOK_RETRIES" env-default:"3"`
+	RetryInterval time.Duration `yaml:"retryInterval" env:"WEBHOOK_RETRY_INTERVAL" env-default:"5s"`
+}
+
+type AirflowConfig struct {
+	URL      string        `yaml:"url" env:"AIRFLOW_URL"`
+	Username string        `yaml:"username" env:"AIRFLOW_USERNAME"`
+	Password string        `yaml:"password" env:"AIRFLOW_PASSWORD"`
+	Timeout  time.Duration `yaml:"timeout" env:"AIRFLOW_TIMEOUT" env-default:"30s"`
+}
+
+// SecretsManagerConfig enables the aws-secretsmanager secret provider. The
+// account and region are used for secrets that are not referred to by ARN.
+type SecretsManagerConfig struct {
+	Enabled bool   `yaml:"enabled" env:"SECRETS_MANAGER_ENABLED" env-default:"false"`
+	Account string `yaml:"account" env:"SECRETS_MANAGER_ACCOUNT"`
+	Region  string `yaml:"region" env:"SECRETS_MANAGER_REGION"`
+}
+
+// SSMConfig enables the ssm secret provider. The account and region are used
+// for parameters that are not referred to by ARN.
+type SSMConfig struct {
+	Enabled bool   `yaml:"enabled" env:"SSM_ENABLED" env-default:"false"`
+	Account string `yaml:"account" env:"SSM_ACCOUNT"`
+	Region  string `yaml:"region" env:"SSM_REGION"`
+}
+
+// SecretFileConfig enables the file secret provider when a path is set. The
+// age key file holds the identities that decrypt SOPS encrypted files.
+type SecretFileConfig struct {
+	Path       string `yaml:"path" env:"SECRET_FILE_PATH"`
+	AgeKeyFile string `yaml:"ageKeyFile" env:"SECRET_FILE_AGE_KEY_FILE"`
+}
+
+type AWSAccount struct {
+	Name       string `yaml:"name"`
+	RoleARN    string `yaml:"roleArn"`
+	ExternalID string `yaml:"externalId"`
+	Region     string `yaml:"region"`
+}
+
 func InitAppConfigs() (*AppConfigs, error) {
 	var appConfigs AppConfigs
 
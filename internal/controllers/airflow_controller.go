@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
+)
+
+type AirflowController struct {
+	airflowService services.IAirflowService
+}
+
+func InitAirflowController(airflowService services.IAirflowService) AirflowController {
+	airflowController := AirflowController{}
+	airflowController.airflowService = airflowService
+	return airflowController
+}
+
+func (h *AirflowController) Release(res http.ResponseWriter, req *http.Request) {
+	requestBody := models.Airflow{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.airflowService.InstallOrUpgradeAirflow(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *AirflowController) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := models.Airflow{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	requestBody.Name = vars["airflow-name"]
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.airflowService.InstallOrUpgradeAirflow(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *AirflowController) GetAllReleaseName(res http.ResponseWriter, req *http.Request) {
+	result, err := h.airflowService.GetAllReleaseName()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *AirflowController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.airflowService.GetReleaseDetail(vars["airflow-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *AirflowController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.airflowService.RemoveAirflow(vars["airflow-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
@@ -117,3 +117,155 @@ func (h *ChartController) RemoveRelease(res http.ResponseWriter, req *http.Reque
 	}
 	helpers.Response(res, 200, nil, "success", "-")
 }
+
+func (h *ChartController) ImportRelease(res http.ResponseWriter, req *http.Request) {
+	requestBody := requests.ImportChart{}
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	err = yaml.Unmarshal([]byte(val), &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.chartService.ImportChart(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *ChartController) RollbackRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := requests.RollbackChart{}
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	err = yaml.Unmarshal([]byte(val), &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	err = h.chartService.RollbackChart(vars["chart-name"], requestBody.Revision)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *ChartController) GetReleaseHistory(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.chartService.GetReleaseHistory(vars["chart-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) TemplateRelease(res http.ResponseWriter, req *http.Request) {
+	requestBody := requests.ChartRelease{}
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	err = yaml.Unmarshal([]byte(val), &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	request, err := requestBody.TransformToModels()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	validate := req.URL.Query().Get("validate") == "true"
+
+	result, err := h.chartService.TemplateChart(request, validate)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) GetReleaseTestResults(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.chartService.GetReleaseTestResults(vars["chart-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) GetReleaseStatus(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.chartService.GetReleaseStatus(vars["chart-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) UploadChartArchive(res http.ResponseWriter, req *http.Request) {
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	if len(val) == 0 {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	result, err := h.chartService.UploadChartArchive(val)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) GetAllChartArchive(res http.ResponseWriter, req *http.Request) {
+	result, err := h.chartService.GetAllChartArchive()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ChartController) RemoveChartArchive(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.chartService.RemoveChartArchive(vars["archive-name"], vars["archive-version"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
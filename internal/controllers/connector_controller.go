@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
+)
+
+type ConnectorController struct {
+	connectorService services.IConnectorService
+}
+
+func InitConnectorController(connectorService services.IConnectorService) ConnectorController {
+	connectorController := ConnectorController{}
+	connectorController.connectorService = connectorService
+	return connectorController
+}
+
+func (h *ConnectorController) Release(res http.ResponseWriter, req *http.Request) {
+	requestBody := models.Connector{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.connectorService.InstallOrUpgradeConnector(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *ConnectorController) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := models.Connector{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	requestBody.Name = vars["connector-name"]
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.connectorService.InstallOrUpgradeConnector(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *ConnectorController) GetAllReleaseName(res http.ResponseWriter, req *http.Request) {
+	result, err := h.connectorService.GetAllReleaseName()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ConnectorController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.connectorService.GetReleaseDetail(vars["connector-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ConnectorController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.connectorService.RemoveConnector(vars["connector-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
+)
+
+type DynamoDBController struct {
+	dynamodbService services.IDynamoDBService
+}
+
+func InitDynamoDBController(dynamodbService services.IDynamoDBService) DynamoDBController {
+	dynamodbController := DynamoDBController{}
+	dynamodbController.dynamodbService = dynamodbService
+	return dynamodbController
+}
+
+func (h *DynamoDBController) Release(res http.ResponseWriter, req *http.Request) {
+	requestBody := models.DynamoDBTable{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.dynamodbService.InstallOrUpgradeTable(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *DynamoDBController) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := models.DynamoDBTable{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	requestBody.Name = vars["table-name"]
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.dynamodbService.InstallOrUpgradeTable(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *DynamoDBController) GetAllReleaseName(res http.ResponseWriter, req *http.Request) {
+	result, err := h.dynamodbService.GetAllReleaseName()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *DynamoDBController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.dynamodbService.GetReleaseDetail(vars["table-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *DynamoDBController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.dynamodbService.RemoveTable(vars["table-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
+)
+
+type FirehoseController struct {
+	firehoseService services.IFirehoseService
+}
+
+func InitFirehoseController(firehoseService services.IFirehoseService) FirehoseController {
+	firehoseController := FirehoseController{}
+	firehoseController.firehoseService = firehoseService
+	return firehoseController
+}
+
+func (h *FirehoseController) Release(res http.ResponseWriter, req *http.Request) {
+	requestBody := models.Firehose{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.firehoseService.InstallOrUpgradeFirehose(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *FirehoseController) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := models.Firehose{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	requestBody.Name = vars["firehose-name"]
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.firehoseService.InstallOrUpgradeFirehose(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *FirehoseController) GetAllReleaseName(res http.ResponseWriter, req *http.Request) {
+	result, err := h.firehoseService.GetAllReleaseName()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *FirehoseController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.firehoseService.GetReleaseDetail(vars["firehose-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *FirehoseController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.firehoseService.RemoveFirehose(vars["firehose-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
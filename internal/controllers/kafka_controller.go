@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
+)
+
+type KafkaController struct {
+	kafkaService services.IKafkaService
+}
+
+func InitKafkaController(kafkaService services.IKafkaService) KafkaController {
+	kafkaController := KafkaController{}
+	kafkaController.kafkaService = kafkaService
+	return kafkaController
+}
+
+func (h *KafkaController) Release(res http.ResponseWriter, req *http.Request) {
+	requestBody := models.KafkaTopic{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.kafkaService.InstallOrUpgradeTopic(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *KafkaController) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	requestBody := models.KafkaTopic{}
+	err := readRequest(req, &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	requestBody.Name = vars["topic-name"]
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.kafkaService.InstallOrUpgradeTopic(requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *KafkaController) GetAllReleaseName(res http.ResponseWriter, req *http.Request) {
+	result, err := h.kafkaService.GetAllReleaseName()
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *KafkaController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.kafkaService.GetReleaseDetail(vars["topic-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *KafkaController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	err := h.kafkaService.RemoveTopic(vars["topic-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helpers"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/requests"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
 	"sigs.k8s.io/yaml"
 )
@@ -87,8 +88,7 @@ func (h *KinesisController) GetAllReleaseName(res http.ResponseWriter, req *http
 }
 
 func (h *KinesisController) GetReleaseDetail(res http.ResponseWriter, req *http.Request) {
-	vars := mux.Vars(req)
-	result, err := h.kinesisService.GetReleaseDetail(vars["kinesis-name"])
+	result, err := h.kinesisService.GetReleaseDetail(kinesisStream(req))
 	if err != nil {
 		helpers.Response(res, 400, nil, "error", err.Error())
 		return
@@ -97,11 +97,48 @@ func (h *KinesisController) GetReleaseDetail(res http.ResponseWriter, req *http.
 }
 
 func (h *KinesisController) RemoveRelease(res http.ResponseWriter, req *http.Request) {
-	vars := mux.Vars(req)
-	err := h.kinesisService.RemoveKinesis(vars["kinesis-name"])
+	err := h.kinesisService.RemoveKinesis(kinesisStream(req))
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, nil, "success", "-")
+}
+
+func (h *KinesisController) ImportRelease(res http.ResponseWriter, req *http.Request) {
+	requestBody := requests.ImportKinesis{}
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	err = yaml.Unmarshal([]byte(val), &requestBody)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	err = h.kinesisService.ImportKinesis(requestBody)
 	if err != nil {
 		helpers.Response(res, 400, nil, "error", err.Error())
 		return
 	}
 	helpers.Response(res, 200, nil, "success", "-")
 }
+
+// kinesisStream reads the stream of the path, in the account and region of
+// the query when either is given.
+func kinesisStream(req *http.Request) requests.KinesisStream {
+	query := req.URL.Query()
+	return requests.KinesisStream{
+		Name:    mux.Vars(req)["kinesis-name"],
+		Account: query.Get("account"),
+		Region:  query.Get("region"),
+		Scoped:  query.Has("account") || query.Has("region"),
+	}
+}
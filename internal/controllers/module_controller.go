@@ -143,3 +143,41 @@ func (h *ModuleController) GetReleaseDetail(res http.ResponseWriter, req *http.R
 	}
 	helpers.Response(res, 200, result, "success", "-")
 }
+
+func (h *ModuleController) TemplateModuleRelease(res http.ResponseWriter, req *http.Request) {
+	requestBody := requests.ModuleRelease{}
+	val, err := ioutil.ReadAll(req.Body)
+	requestBody.Name = req.Header.Get("NAME")
+	requestBody.ModuleName = req.Header.Get("MODULE_NAME")
+	requestBody.Version = req.Header.Get("VERSION")
+	requestBody.Values = string(val)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	if requestBody.IsEmpty() {
+		helpers.Response(res, 400, nil, "error", "cannot process empty request")
+		return
+	}
+
+	module, moduleRelease, _ := requestBody.TransformToModels(false)
+	validate := req.URL.Query().Get("validate") == "true"
+
+	result, err := h.moduleService.TemplateModuleRelease(module, moduleRelease, validate)
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+
+	helpers.Response(res, 200, result, "success", "-")
+}
+
+func (h *ModuleController) GetReleaseStatus(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	result, err := h.moduleService.GetReleaseStatus(vars["release-name"])
+	if err != nil {
+		helpers.Response(res, 400, nil, "error", err.Error())
+		return
+	}
+	helpers.Response(res, 200, result, "success", "-")
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"sigs.k8s.io/yaml"
+)
+
+// readRequest decodes the YAML or JSON body of the request into requestBody.
+func readRequest(req *http.Request, requestBody interface{}) error {
+	val, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(val, requestBody)
+}
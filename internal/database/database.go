@@ -2,8 +2,10 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
@@ -41,6 +43,16 @@ func GetDB(config configs.DBConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	err = database.AutoMigrate(&models.ChartTestResult{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.ChartArchive{})
+	if err != nil {
+		return nil, err
+	}
+
 	err = database.AutoMigrate(&models.Module{})
 	if err != nil {
 		return nil, err
@@ -51,11 +63,131 @@ func GetDB(config configs.DBConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// streams used to be soft deleted, which would keep their name, account
+	// and region taken once they are unique
+	if database.Migrator().HasTable(&models.Kinesis{}) && !database.Migrator().HasIndex(&models.Kinesis{}, "kinesis_stream") {
+		err = database.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Kinesis{}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = database.AutoMigrate(&models.Kinesis{})
 	if err != nil {
 		return nil, err
 	}
 
+	err = migrateKinesisTags(database)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.KinesisConsumer{})
+	if err != nil {
+		return nil, err
+	}
+
+	// consumers used to be unique by stream name only, the account and region
+	// of the recorded ones are those of their stream
+	if database.Migrator().HasIndex(&models.KinesisConsumer{}, "kinesis_consumer_search") {
+		err = database.Migrator().DropIndex(&models.KinesisConsumer{}, "kinesis_consumer_search")
+		if err != nil {
+			return nil, err
+		}
+		err = database.Exec("UPDATE kinesis_consumers SET account = k.account, region = k.region FROM kineses k WHERE k.name = kinesis_consumers.stream_name AND k.deleted_at IS NULL").Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = database.AutoMigrate(&models.Firehose{})
+	if err != nil {
+		return nil, err
+	}
+
+	// redshift passwords used to be stored in plain text
+	if database.Migrator().HasColumn(&models.Firehose{}, "redshift_password") {
+		err = database.Migrator().DropColumn(&models.Firehose{}, "redshift_password")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = database.AutoMigrate(&models.S3Bucket{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.SQSQueue{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.SNSTopic{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.KafkaTopic{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.Manifest{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.ManifestObject{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.Connector{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.Schema{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.RegisteredSchema{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.PostgresDatabase{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.DynamoDBTable{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.Webhook{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.WebhookResult{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.Airflow{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(&models.KubernetesSecret{})
+	if err != nil {
+		return nil, err
+	}
+
 	return database, nil
 }
 
@@ -63,6 +195,43 @@ type zerologAdapter struct {
 	log zerolog.Logger
 }
 
+// migrateKinesisTags converts the free-form tags kinesis streams used to have
+// into the JSON object they are now stored as, reading them as comma separated
+// key=value pairs.
+func migrateKinesisTags(database *gorm.DB) error {
+	var streams []struct {
+		ID   uint
+		Tags string
+	}
+	result := database.Table("kineses").Select("id", "tags").Where("tags <> '' AND tags NOT LIKE '{%'").Find(&streams)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, stream := range streams {
+		tags := map[string]string{}
+		for _, pair := range strings.Split(stream.Tags, ",") {
+			key, value := pair, ""
+			if i := strings.IndexAny(pair, "=:"); i >= 0 {
+				key, value = pair[:i], pair[i+1:]
+			}
+			key = strings.TrimSpace(key)
+			if key != "" {
+				tags[key] = strings.TrimSpace(value)
+			}
+		}
+		converted, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+		result = database.Table("kineses").Where("id = ?", stream.ID).Update("tags", string(converted))
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
 func (z *zerologAdapter) LogMode(level logger.LogLevel) logger.Interface {
 	switch level {
 	case logger.Error:
@@ -15,34 +15,50 @@ import (
 
 var helmClient helm.Client
 
+var restConfig *rest.Config
+
+// GetRestConfig returns the kubernetes rest config shared by the helm clients
+// and the manifest provider. It is built once, as the kubeconfig flag can only
+// be registered a single time.
+func GetRestConfig(authConfig configs.AuthConfig) (*rest.Config, error) {
+	if restConfig != nil {
+		return restConfig, nil
+	}
+	var config *rest.Config
+	var err error
+
+	switch authConfig.Method {
+	case "kubeconfig":
+		var kubeconfig *string
+		if home := homedir.HomeDir(); home != "" {
+			kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
+		} else {
+			kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+		}
+		flag.Parse()
+
+		config, err = clientcmd.BuildConfigFromFlags("", *kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+	case "service-account":
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	restConfig = config
+	return restConfig, nil
+}
+
 func GetHelmClient(authConfig configs.AuthConfig) (helm.Client, error) {
 	// func GetHelmClient() (helm.Client, error) {
 
 	if helmClient == nil {
-		var config *rest.Config
-		var err error
-
-		switch authConfig.Method {
-		// switch "kubeconfig" {
-
-		case "kubeconfig":
-			var kubeconfig *string
-			if home := homedir.HomeDir(); home != "" {
-				kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-			} else {
-				kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-			}
-			flag.Parse()
-
-			config, err = clientcmd.BuildConfigFromFlags("", *kubeconfig)
-			if err != nil {
-				return nil, err
-			}
-		case "service-account":
-			config, err = rest.InClusterConfig()
-			if err != nil {
-				panic(err.Error())
-			}
+		config, err := GetRestConfig(authConfig)
+		if err != nil {
+			return nil, err
 		}
 
 		opt := &helm.RestConfClientOptions{
@@ -69,28 +85,9 @@ func GetHelmClient(authConfig configs.AuthConfig) (helm.Client, error) {
 
 func GenerateHelmClient(authConfig configs.AuthConfig) (map[string]helm.Client, error) {
 	helmClient := map[string]helm.Client{}
-	var config *rest.Config
-	var err error
-
-	switch authConfig.Method {
-	case "kubeconfig":
-		var kubeconfig *string
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-		} else {
-			kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-		}
-		flag.Parse()
-
-		config, err = clientcmd.BuildConfigFromFlags("", *kubeconfig)
-		if err != nil {
-			return nil, err
-		}
-	case "service-account":
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			panic(err.Error())
-		}
+	config, err := GetRestConfig(authConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	for _, namespace := range authConfig.AvailableNamespace {
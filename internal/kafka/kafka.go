@@ -0,0 +1,37 @@
+package kafka
+
+import (
+	"crypto/tls"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+var kafkaClient *kafka.Client
+
+// GetKafkaClient returns the admin client of the configured cluster. SASL/PLAIN
+// is used when a username is configured.
+func GetKafkaClient(kafkaConfig configs.KafkaConfig) *kafka.Client {
+	if kafkaClient != nil {
+		return kafkaClient
+	}
+
+	transport := &kafka.Transport{}
+	if kafkaConfig.TLS {
+		transport.TLS = &tls.Config{}
+	}
+	if kafkaConfig.Username != "" {
+		transport.SASL = plain.Mechanism{
+			Username: kafkaConfig.Username,
+			Password: kafkaConfig.Password,
+		}
+	}
+
+	kafkaClient = &kafka.Client{
+		Addr:      kafka.TCP(kafkaConfig.Brokers...),
+		Timeout:   kafkaConfig.Timeout,
+		Transport: transport,
+	}
+	return kafkaClient
+}
@@ -0,0 +1,134 @@
+package kafkaconnect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+// ErrNotFound is returned when the connector does not exist on the cluster.
+var ErrNotFound = errors.New("connector not found")
+
+// Client talks to the REST API of a Kafka Connect cluster.
+type Client struct {
+	connectConfig configs.KafkaConnectConfig
+	httpClient    *http.Client
+}
+
+type ConnectorStatus struct {
+	Name      string      `json:"name"`
+	Connector TaskState   `json:"connector"`
+	Tasks     []TaskState `json:"tasks"`
+}
+
+type TaskState struct {
+	ID       int    `json:"id"`
+	State    string `json:"state"`
+	WorkerID string `json:"worker_id"`
+	Trace    string `json:"trace,omitempty"`
+}
+
+var connectClient *Client
+
+func GetKafkaConnectClient(connectConfig configs.KafkaConnectConfig) *Client {
+	if connectClient != nil {
+		return connectClient
+	}
+	connectClient = &Client{}
+	connectClient.connectConfig = connectConfig
+	connectClient.httpClient = &http.Client{
+		Timeout: connectConfig.Timeout,
+	}
+	return connectClient
+}
+
+// PutConfig creates the connector, or replaces the config of an existing one.
+func (c *Client) PutConfig(ctx context.Context, name string, config map[string]string) error {
+	return c.do(ctx, http.MethodPut, "/connectors/"+url.PathEscape(name)+"/config", config, nil)
+}
+
+func (c *Client) GetConfig(ctx context.Context, name string) (map[string]string, error) {
+	config := map[string]string{}
+	err := c.do(ctx, http.MethodGet, "/connectors/"+url.PathEscape(name)+"/config", nil, &config)
+	return config, err
+}
+
+func (c *Client) GetStatus(ctx context.Context, name string) (ConnectorStatus, error) {
+	status := ConnectorStatus{}
+	err := c.do(ctx, http.MethodGet, "/connectors/"+url.PathEscape(name)+"/status", nil, &status)
+	return status, err
+}
+
+func (c *Client) Pause(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, "/connectors/"+url.PathEscape(name)+"/pause", nil, nil)
+}
+
+func (c *Client) Resume(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, "/connectors/"+url.PathEscape(name)+"/resume", nil, nil)
+}
+
+func (c *Client) Delete(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/connectors/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	if c.connectConfig.URL == "" {
+		return errors.New("no kafka connect url configured")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.connectConfig.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.connectConfig.Username != "" {
+		req.SetBasicAuth(c.connectConfig.Username, c.connectConfig.Password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if res.StatusCode >= 300 {
+		// errors come as {"error_code": int, "message": string}
+		connectError := struct {
+			Message string `json:"message"`
+		}{}
+		data, _ := ioutil.ReadAll(res.Body)
+		if json.Unmarshal(data, &connectError) == nil && connectError.Message != "" {
+			return fmt.Errorf("kafka connect %s %s: %s", method, path, connectError.Message)
+		}
+		return fmt.Errorf("kafka connect %s %s: %s", method, path, res.Status)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// Airflow is a set of connections, variables and pools used by the DAGs of a
+// module. Secret values are not stored, only the references they are read
+// from at release time. Connections, variables and pools that already exist
+// are only managed when Import is set.
+type Airflow struct {
+	Model
+	ModuleReleaseID uint               `json:"-"`
+	Name            string             `json:"name"`
+	Connections     AirflowConnections `gorm:"type:text" json:"connections"`
+	Variables       AirflowVariables   `gorm:"type:text" json:"variables"`
+	Pools           AirflowPools       `gorm:"type:text" json:"pools"`
+	Import          bool               `json:"import"`
+	Revision        int                `json:"revision"`
+}
+
+// AirflowConnection is sent with Extra, extended with the secrets
+// ExtraSecrets refers to.
+type AirflowConnection struct {
+	ID             string                 `json:"id"`
+	Type           string                 `json:"type"`
+	Host           string                 `json:"host,omitempty"`
+	Login          string                 `json:"login,omitempty"`
+	Schema         string                 `json:"schema,omitempty"`
+	Port           int                    `json:"port,omitempty"`
+	PasswordSecret *SecretReference       `json:"password_secret,omitempty"`
+	Extra          map[string]interface{} `json:"extra,omitempty"`
+	ExtraSecrets   SecretReferences       `json:"extra_secrets,omitempty"`
+}
+
+// AirflowVariable is set to Value, or to the secret ValueSecret refers to.
+type AirflowVariable struct {
+	Key         string           `json:"key"`
+	Value       string           `json:"value,omitempty"`
+	ValueSecret *SecretReference `json:"value_secret,omitempty"`
+}
+
+type AirflowPool struct {
+	Name  string `json:"name"`
+	Slots int    `json:"slots"`
+}
+
+// AirflowConnections is a list of connections stored as a JSON text column.
+type AirflowConnections []AirflowConnection
+
+func (a AirflowConnections) Value() (driver.Value, error) {
+	if a == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(a)
+	return string(value), err
+}
+
+func (a *AirflowConnections) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, a)
+}
+
+// AirflowVariables is a list of variables stored as a JSON text column.
+type AirflowVariables []AirflowVariable
+
+func (a AirflowVariables) Value() (driver.Value, error) {
+	if a == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(a)
+	return string(value), err
+}
+
+func (a *AirflowVariables) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, a)
+}
+
+// AirflowPools is a list of pools stored as a JSON text column.
+type AirflowPools []AirflowPool
+
+func (a AirflowPools) Value() (driver.Value, error) {
+	if a == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(a)
+	return string(value), err
+}
+
+func (a *AirflowPools) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, a)
+}
+
+func (a Airflow) IsEmpty() bool {
+	return reflect.DeepEqual(a, Airflow{})
+}
@@ -1,18 +1,48 @@
 package models
 
 import (
+	"time"
+
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
 )
 
 type ChartRelease struct {
 	Model
-	ModuleReleaseID uint   `json:"-"`
-	Name            string `json:"name"`
-	ReleaseName     string `json:"release_name"`
-	Version         string `json:"version"`
-	Values          string `json:"values"`
-	Revision        int    `json:"revision"`
-	Namespace       string `json:"namespace"`
+	ModuleReleaseID uint         `json:"-"`
+	Name            string       `json:"name"`
+	ReleaseName     string       `json:"release_name"`
+	Version         string       `json:"version"`
+	Values          string       `json:"values"`
+	Revision        int          `json:"revision"`
+	Namespace       string       `json:"namespace"`
+	Options         ChartOptions `gorm:"embedded;embeddedPrefix:option_" json:"options"`
+}
+
+type ChartOptions struct {
+	Timeout         string `json:"timeout,omitempty"`
+	Wait            *bool  `json:"wait,omitempty"`
+	WaitForJobs     bool   `json:"waitForJobs,omitempty"`
+	Atomic          bool   `json:"atomic,omitempty"`
+	CreateNamespace bool   `json:"createNamespace,omitempty"`
+	SkipCRDs        bool   `gorm:"column:skip_crds" json:"skipCRDs,omitempty"`
+	Force           bool   `json:"force,omitempty"`
+	DisableHooks    bool   `json:"disableHooks,omitempty"`
+	MaxHistory      int    `json:"maxHistory,omitempty"`
+	CleanupOnFail   bool   `json:"cleanupOnFail,omitempty"`
+	Description     string `json:"description,omitempty"`
+	RunTests        bool   `json:"runTests,omitempty"`
+}
+
+type ChartTestResult struct {
+	Model
+	ReleaseName string    `json:"release_name"`
+	Namespace   string    `json:"namespace"`
+	Revision    int       `json:"revision"`
+	Name        string    `json:"name"`
+	Phase       string    `json:"phase"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Logs        string    `json:"logs"`
 }
 
 func (c ChartRelease) TransformToResponse() responses.ChartRelease {
@@ -23,6 +53,7 @@ func (c ChartRelease) TransformToResponse() responses.ChartRelease {
 		Values:      c.Values,
 		Revision:    c.Revision,
 		Namespace:   c.Namespace,
+		Options:     c.Options,
 	}
 	return response
 }
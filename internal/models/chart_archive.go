@@ -0,0 +1,8 @@
+package models
+
+type ChartArchive struct {
+	Model
+	Name    string `gorm:"uniqueIndex:chart_archive_search" json:"name"`
+	Version string `gorm:"uniqueIndex:chart_archive_search" json:"version"`
+	Content []byte `json:"-"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "reflect"
+
+// Connector is a Kafka Connect connector. Secrets are config keys whose
+// values are read from the secret providers when the config is sent to Kafka
+// Connect, so credentials such as database.password are never stored. A
+// connector that already exists is only managed when Import is set.
+type Connector struct {
+	Model
+	ModuleReleaseID uint             `json:"-"`
+	Name            string           `json:"name"`
+	Config          StringMap        `gorm:"type:text" json:"config"`
+	Secrets         SecretReferences `gorm:"type:text" json:"secrets"`
+	Paused          bool             `json:"paused"`
+	Import          bool             `json:"import"`
+	Revision        int              `json:"revision"`
+}
+
+func (c Connector) IsEmpty() bool {
+	return reflect.DeepEqual(c, Connector{})
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	DynamoDBDeletionPolicyRetain        = "retain"
+	DynamoDBDeletionPolicyDeleteIfEmpty = "delete-if-empty"
+	DynamoDBDeletionPolicyForce         = "force"
+)
+
+type DynamoDBTable struct {
+	Model
+	ModuleReleaseID        uint            `json:"-"`
+	Name                   string          `json:"name"`
+	Account                string          `json:"account"`
+	Region                 string          `json:"region"`
+	PartitionKey           DynamoDBKey     `gorm:"embedded;embeddedPrefix:partition_key_" json:"partition_key"`
+	SortKey                DynamoDBKey     `gorm:"embedded;embeddedPrefix:sort_key_" json:"sort_key"`
+	BillingMode            string          `json:"billing_mode"`
+	ReadCapacity           int64           `json:"read_capacity"`
+	WriteCapacity          int64           `json:"write_capacity"`
+	TTLAttribute           string          `json:"ttl_attribute"`
+	PointInTimeRecovery    bool            `json:"point_in_time_recovery"`
+	GlobalSecondaryIndexes DynamoDBIndexes `gorm:"type:text" json:"global_secondary_indexes"`
+	Tags                   StringMap       `gorm:"type:text" json:"tags"`
+	DeletionPolicy         string          `json:"deletion_policy"`
+	Revision               int             `json:"revision"`
+}
+
+// DynamoDBKey is a key attribute of a table or an index. An empty name means
+// the key is not used, which is only allowed for sort keys.
+type DynamoDBKey struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type DynamoDBIndex struct {
+	Name             string      `json:"name"`
+	PartitionKey     DynamoDBKey `json:"partition_key"`
+	SortKey          DynamoDBKey `json:"sort_key"`
+	ProjectionType   string      `json:"projection_type,omitempty"`
+	NonKeyAttributes []string    `json:"non_key_attributes,omitempty"`
+	ReadCapacity     int64       `json:"read_capacity,omitempty"`
+	WriteCapacity    int64       `json:"write_capacity,omitempty"`
+}
+
+// DynamoDBIndexes is a list of global secondary indexes stored as a JSON text
+// column.
+type DynamoDBIndexes []DynamoDBIndex
+
+func (d DynamoDBIndexes) Value() (driver.Value, error) {
+	if d == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(d)
+	return string(value), err
+}
+
+func (d *DynamoDBIndexes) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, d)
+}
+
+func (d DynamoDBTable) IsEmpty() bool {
+	return reflect.DeepEqual(d, DynamoDBTable{})
+}
@@ -0,0 +1,44 @@
+package models
+
+import "reflect"
+
+const (
+	FirehoseDestinationS3       = "s3"
+	FirehoseDestinationRedshift = "redshift"
+)
+
+type Firehose struct {
+	Model
+	ModuleReleaseID          uint             `json:"-"`
+	Name                     string           `json:"name"`
+	Account                  string           `json:"account"`
+	Region                   string           `json:"region"`
+	SourceStream             string           `json:"source_stream"`
+	SourceRoleARN            string           `gorm:"column:source_role_arn" json:"source_role_arn"`
+	Destination              string           `json:"destination"`
+	RoleARN                  string           `gorm:"column:role_arn" json:"role_arn"`
+	BucketARN                string           `gorm:"column:bucket_arn" json:"bucket_arn"`
+	Prefix                   string           `json:"prefix"`
+	ErrorOutputPrefix        string           `json:"error_output_prefix"`
+	BufferingSizeMB          int32            `gorm:"column:buffering_size_mb" json:"buffering_size_mb"`
+	BufferingIntervalSeconds int32            `json:"buffering_interval_seconds"`
+	CompressionFormat        string           `json:"compression_format"`
+	Redshift                 FirehoseRedshift `gorm:"embedded;embeddedPrefix:redshift_" json:"redshift"`
+	Revision                 int              `json:"revision"`
+}
+
+// FirehoseRedshift is a Redshift destination. The password is read from the
+// secret providers when the delivery stream is created or updated, only the
+// reference to it is stored.
+type FirehoseRedshift struct {
+	ClusterJDBCURL string          `gorm:"column:cluster_jdbc_url" json:"cluster_jdbc_url,omitempty"`
+	Username       string          `json:"username,omitempty"`
+	PasswordSecret SecretReference `gorm:"embedded;embeddedPrefix:password_secret_" json:"password_secret"`
+	Table          string          `json:"table,omitempty"`
+	Columns        string          `json:"columns,omitempty"`
+	CopyOptions    string          `json:"copy_options,omitempty"`
+}
+
+func (f Firehose) IsEmpty() bool {
+	return reflect.DeepEqual(f, Firehose{})
+}
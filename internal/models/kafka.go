@@ -0,0 +1,20 @@
+package models
+
+import "reflect"
+
+// KafkaTopic is a topic of the configured cluster. A topic that already
+// exists is only managed when Import is set.
+type KafkaTopic struct {
+	Model
+	ModuleReleaseID   uint      `json:"-"`
+	Name              string    `json:"name"`
+	Partitions        int       `json:"partitions"`
+	ReplicationFactor int       `json:"replication_factor"`
+	Configs           StringMap `gorm:"type:text" json:"configs"`
+	Import            bool      `json:"import"`
+	Revision          int       `json:"revision"`
+}
+
+func (k KafkaTopic) IsEmpty() bool {
+	return reflect.DeepEqual(k, KafkaTopic{})
+}
@@ -2,16 +2,41 @@ package models
 
 import "reflect"
 
+const (
+	KinesisStreamModeProvisioned = "PROVISIONED"
+	KinesisStreamModeOnDemand    = "ON_DEMAND"
+)
+
+// Kinesis is a stream of an aws account, streams of different accounts and
+// regions sharing a name.
 type Kinesis struct {
 	Model
-	ModuleReleaseID uint   `json:"-"`
-	Name            string `json:"name"`
-	Region          string `json:"region"`
-	Shards          int32  `json:"shards"`
-	Tags            string `json:"tags"`
-	Revision        int    `json:"revision"`
+	ModuleReleaseID      uint       `json:"-"`
+	Name                 string     `gorm:"uniqueIndex:kinesis_stream" json:"name"`
+	Account              string     `gorm:"uniqueIndex:kinesis_stream" json:"account"`
+	Region               string     `gorm:"uniqueIndex:kinesis_stream" json:"region"`
+	Shards               int32      `json:"shards"`
+	StreamMode           string     `json:"stream_mode"`
+	RetentionPeriodHours int32      `json:"retention_period_hours"`
+	EncryptionKeyID      string     `json:"encryption_key_id"`
+	ShardLevelMetrics    StringList `gorm:"type:text" json:"shard_level_metrics"`
+	Tags                 StringMap  `gorm:"type:text" json:"tags"`
+	Consumers            StringList `gorm:"type:text" json:"consumers"`
+	Revision             int        `json:"revision"`
 }
 
 func (k Kinesis) IsEmpty() bool {
 	return reflect.DeepEqual(k, Kinesis{})
 }
+
+// KinesisConsumer records an enhanced fan-out consumer registered by the
+// controller on a stream, streams of different accounts and regions sharing
+// a name.
+type KinesisConsumer struct {
+	Model
+	StreamName string `gorm:"uniqueIndex:kinesis_consumer_stream" json:"stream_name"`
+	Account    string `gorm:"uniqueIndex:kinesis_consumer_stream" json:"account"`
+	Region     string `gorm:"uniqueIndex:kinesis_consumer_stream" json:"region"`
+	Name       string `gorm:"uniqueIndex:kinesis_consumer_stream" json:"name"`
+	ARN        string `gorm:"column:arn" json:"arn"`
+}
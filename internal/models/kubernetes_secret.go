@@ -0,0 +1,21 @@
+package models
+
+import "reflect"
+
+// KubernetesSecret is a kubernetes Secret whose data is read from the secret
+// providers, so charts can refer to it by name instead of receiving the
+// values. Data maps the keys of the Secret to the secrets they are read from.
+type KubernetesSecret struct {
+	Model
+	ModuleReleaseID uint             `json:"-"`
+	Name            string           `json:"name"`
+	Namespace       string           `json:"namespace"`
+	Type            string           `json:"type"`
+	Data            SecretReferences `gorm:"type:text" json:"data"`
+	Labels          StringMap        `gorm:"type:text" json:"labels"`
+	Revision        int              `json:"revision"`
+}
+
+func (k KubernetesSecret) IsEmpty() bool {
+	return reflect.DeepEqual(k, KubernetesSecret{})
+}
@@ -0,0 +1,29 @@
+package models
+
+import "reflect"
+
+type Manifest struct {
+	Model
+	ModuleReleaseID uint   `json:"-"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Manifests       string `gorm:"type:text" json:"manifests"`
+	Revision        int    `json:"revision"`
+}
+
+func (m Manifest) IsEmpty() bool {
+	return reflect.DeepEqual(m, Manifest{})
+}
+
+// ManifestObject is an inventory entry of a kubernetes object applied by a
+// manifest, used to prune the objects removed from it.
+type ManifestObject struct {
+	Model
+	ModuleReleaseID uint   `json:"-"`
+	ManifestName    string `gorm:"uniqueIndex:manifest_object_search" json:"manifest_name"`
+	Group           string `gorm:"column:api_group;uniqueIndex:manifest_object_search" json:"group"`
+	Version         string `json:"version"`
+	Kind            string `gorm:"uniqueIndex:manifest_object_search" json:"kind"`
+	Namespace       string `gorm:"uniqueIndex:manifest_object_search" json:"namespace"`
+	Name            string `gorm:"uniqueIndex:manifest_object_search" json:"name"`
+}
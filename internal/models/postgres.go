@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	PostgresDeletionPolicyRetain = "retain"
+	PostgresDeletionPolicyDelete = "delete"
+)
+
+// PostgresDatabase is a database of the warehouse postgres together with its
+// owning login role. Name is used for both unless Role is set. An existing
+// database or role not created by the controller is only used when Import is
+// set, and is never dropped.
+type PostgresDatabase struct {
+	Model
+	ModuleReleaseID uint           `json:"-"`
+	Name            string         `json:"name"`
+	Role            string         `json:"role"`
+	Schemas         StringList     `gorm:"type:text" json:"schemas"`
+	Grants          PostgresGrants `gorm:"type:text" json:"grants"`
+	DeletionPolicy  string         `json:"deletion_policy"`
+	Import          bool           `json:"import"`
+	Revision        int            `json:"revision"`
+}
+
+// PostgresGrant grants an existing role privileges on the tables of a schema.
+type PostgresGrant struct {
+	Role       string   `json:"role"`
+	Schema     string   `json:"schema"`
+	Privileges []string `json:"privileges"`
+}
+
+// PostgresGrants is a list of grants stored as a JSON text column.
+type PostgresGrants []PostgresGrant
+
+func (p PostgresGrants) Value() (driver.Value, error) {
+	if p == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(p)
+	return string(value), err
+}
+
+func (p *PostgresGrants) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, p)
+}
+
+func (p PostgresDatabase) IsEmpty() bool {
+	return reflect.DeepEqual(p, PostgresDatabase{})
+}
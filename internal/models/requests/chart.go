@@ -13,6 +13,7 @@ type ChartRelease struct {
 	Version     string      `json:"version"`
 	Values      interface{} `json:"values"`
 	Namespace   string      `json:"namespace"`
+	models.ChartOptions
 }
 
 func (c ChartRelease) TransformToModels() (models.ChartRelease, error) {
@@ -23,6 +24,7 @@ func (c ChartRelease) TransformToModels() (models.ChartRelease, error) {
 		Version:     c.Version,
 		Values:      string(values),
 		Namespace:   c.Namespace,
+		Options:     c.ChartOptions,
 	}
 	return releaseModels, err
 }
@@ -30,3 +32,18 @@ func (c ChartRelease) TransformToModels() (models.ChartRelease, error) {
 func (c ChartRelease) IsEmpty() bool {
 	return reflect.DeepEqual(c, ChartRelease{})
 }
+
+type ImportChart struct {
+	Name          string `json:"name"`
+	ReleaseName   string `json:"release_name"`
+	Namespace     string `json:"namespace"`
+	ModuleRelease string `json:"module_release"`
+}
+
+func (c ImportChart) IsEmpty() bool {
+	return c.ReleaseName == ""
+}
+
+type RollbackChart struct {
+	Revision int `json:"revision"`
+}
@@ -0,0 +1,21 @@
+package requests
+
+type ImportKinesis struct {
+	Name          string `json:"name"`
+	Account       string `json:"account"`
+	Region        string `json:"region"`
+	ModuleRelease string `json:"module_release"`
+}
+
+func (k ImportKinesis) IsEmpty() bool {
+	return k.Name == ""
+}
+
+// KinesisStream names a recorded stream. The account and region are only
+// matched when Scoped is set, otherwise the name alone has to be unique.
+type KinesisStream struct {
+	Name    string
+	Account string
+	Region  string
+	Scoped  bool
+}
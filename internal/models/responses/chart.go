@@ -1,5 +1,7 @@
 package responses
 
+import "time"
+
 type ChartRelease struct {
 	Name        string      `json:"name"`
 	ReleaseName string      `json:"release_name"`
@@ -7,4 +9,21 @@ type ChartRelease struct {
 	Values      interface{} `json:"values"`
 	Revision    int         `json:"revision"`
 	Namespace   string      `json:"namespace"`
+	Options     interface{} `json:"options"`
+}
+
+type ChartHistory struct {
+	Revision    int       `json:"revision"`
+	Updated     time.Time `json:"updated"`
+	Status      string    `json:"status"`
+	Chart       string    `json:"chart"`
+	AppVersion  string    `json:"app_version"`
+	Description string    `json:"description"`
+}
+
+type ChartManifest struct {
+	Name        string `json:"name"`
+	ReleaseName string `json:"release_name"`
+	Namespace   string `json:"namespace"`
+	Manifest    string `json:"manifest"`
 }
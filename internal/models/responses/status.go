@@ -0,0 +1,167 @@
+package responses
+
+import "time"
+
+type ComponentStatus struct {
+	Name    string      `json:"name"`
+	Healthy bool        `json:"healthy"`
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Detail  interface{} `json:"detail,omitempty"`
+}
+
+type ModuleReleaseStatus struct {
+	Name       string                       `json:"name"`
+	Module     string                       `json:"module"`
+	Version    string                       `json:"version"`
+	Revision   int                          `json:"revision"`
+	Healthy    bool                         `json:"healthy"`
+	Components map[string][]ComponentStatus `json:"components"`
+}
+
+type ChartStatus struct {
+	ReleaseName string           `json:"release_name"`
+	Namespace   string           `json:"namespace"`
+	Revision    int              `json:"revision"`
+	Status      string           `json:"status"`
+	Resources   []ResourceStatus `json:"resources"`
+}
+
+type ResourceStatus struct {
+	Kind            string          `json:"kind"`
+	Name            string          `json:"name"`
+	Ready           bool            `json:"ready"`
+	DesiredReplicas int32           `json:"desired_replicas,omitempty"`
+	ReadyReplicas   int32           `json:"ready_replicas,omitempty"`
+	Restarts        int32           `json:"restarts,omitempty"`
+	Message         string          `json:"message,omitempty"`
+	Events          []ResourceEvent `json:"events,omitempty"`
+}
+
+type ResourceEvent struct {
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+type KinesisStatus struct {
+	Name                 string `json:"name"`
+	Status               string `json:"status"`
+	OpenShardCount       int32  `json:"open_shard_count"`
+	RetentionPeriodHours int32  `json:"retention_period_hours"`
+	ConsumerCount        int32  `json:"consumer_count"`
+}
+
+type SQSStatus struct {
+	Name                        string `json:"name"`
+	URL                         string `json:"url"`
+	ARN                         string `json:"arn"`
+	ApproximateMessages         string `json:"approximate_messages"`
+	ApproximateMessagesInFlight string `json:"approximate_messages_in_flight"`
+	ApproximateMessagesDelayed  string `json:"approximate_messages_delayed"`
+}
+
+type SNSStatus struct {
+	Name                   string `json:"name"`
+	ARN                    string `json:"arn"`
+	SubscriptionsConfirmed string `json:"subscriptions_confirmed"`
+	SubscriptionsPending   string `json:"subscriptions_pending"`
+}
+
+type KafkaStatus struct {
+	Name              string `json:"name"`
+	Partitions        int    `json:"partitions"`
+	ReplicationFactor int    `json:"replication_factor"`
+	UnderReplicated   int    `json:"under_replicated_partitions"`
+	OfflinePartitions int    `json:"offline_partitions"`
+}
+
+type ManifestStatus struct {
+	Name    string                 `json:"name"`
+	Objects []ManifestObjectStatus `json:"objects"`
+}
+
+type ManifestObjectStatus struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Exists     bool   `json:"exists"`
+}
+
+type ConnectorStatus struct {
+	Name   string                `json:"name"`
+	State  string                `json:"state"`
+	Worker string                `json:"worker"`
+	Tasks  []ConnectorTaskStatus `json:"tasks"`
+}
+
+type ConnectorTaskStatus struct {
+	ID     int    `json:"id"`
+	State  string `json:"state"`
+	Worker string `json:"worker"`
+	Trace  string `json:"trace,omitempty"`
+}
+
+type SchemaStatus struct {
+	Subject       string `json:"subject"`
+	SchemaID      int    `json:"schema_id"`
+	Version       int    `json:"version"`
+	Compatibility string `json:"compatibility"`
+}
+
+type PostgresStatus struct {
+	Database       string `json:"database"`
+	Role           string `json:"role"`
+	DatabaseExists bool   `json:"database_exists"`
+	RoleExists     bool   `json:"role_exists"`
+	SecretExists   bool   `json:"secret_exists"`
+}
+
+type DynamoDBStatus struct {
+	Name      string                `json:"name"`
+	ARN       string                `json:"arn"`
+	Status    string                `json:"status"`
+	ItemCount int64                 `json:"item_count"`
+	SizeBytes int64                 `json:"size_bytes"`
+	Indexes   []DynamoDBIndexStatus `json:"indexes"`
+}
+
+type DynamoDBIndexStatus struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Backfilling bool   `json:"backfilling"`
+}
+
+type WebhookStatus struct {
+	Name       string            `json:"name"`
+	Action     string            `json:"action"`
+	StatusCode int               `json:"status_code"`
+	Outputs    map[string]string `json:"outputs"`
+	CalledAt   time.Time         `json:"called_at"`
+}
+
+type AirflowStatus struct {
+	MissingConnections []string            `json:"missing_connections,omitempty"`
+	MissingVariables   []string            `json:"missing_variables,omitempty"`
+	MissingPools       []string            `json:"missing_pools,omitempty"`
+	Pools              []AirflowPoolStatus `json:"pools,omitempty"`
+}
+
+type AirflowPoolStatus struct {
+	Name         string `json:"name"`
+	Slots        int    `json:"slots"`
+	OpenSlots    int    `json:"open_slots"`
+	RunningSlots int    `json:"running_slots"`
+	QueuedSlots  int    `json:"queued_slots"`
+}
+
+type KubernetesSecretStatus struct {
+	Name         string   `json:"name"`
+	Namespace    string   `json:"namespace"`
+	Type         string   `json:"type"`
+	MissingKeys  []string `json:"missing_keys,omitempty"`
+	OutdatedKeys []string `json:"outdated_keys,omitempty"`
+}
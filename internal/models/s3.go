@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	S3DeletionPolicyRetain        = "retain"
+	S3DeletionPolicyDeleteIfEmpty = "delete-if-empty"
+	S3DeletionPolicyForce         = "force"
+)
+
+// S3Bucket is a bucket of an aws account. A bucket that already exists in the
+// account is only managed when Import is set.
+type S3Bucket struct {
+	Model
+	ModuleReleaseID   uint             `json:"-"`
+	Name              string           `json:"name"`
+	Account           string           `json:"account"`
+	Region            string           `json:"region"`
+	Versioning        bool             `json:"versioning"`
+	EncryptionKeyID   string           `json:"encryption_key_id"`
+	BlockPublicAccess *bool            `json:"block_public_access"`
+	LifecycleRules    S3LifecycleRules `gorm:"type:text" json:"lifecycle_rules"`
+	Tags              StringMap        `gorm:"type:text" json:"tags"`
+	DeletionPolicy    string           `json:"deletion_policy"`
+	Import            bool             `json:"import"`
+	Revision          int              `json:"revision"`
+}
+
+type S3LifecycleRule struct {
+	ID                        string         `json:"id"`
+	Prefix                    string         `json:"prefix,omitempty"`
+	ExpirationDays            int32          `json:"expiration_days,omitempty"`
+	NoncurrentExpirationDays  int32          `json:"noncurrent_expiration_days,omitempty"`
+	AbortIncompleteUploadDays int32          `json:"abort_incomplete_upload_days,omitempty"`
+	Transitions               []S3Transition `json:"transitions,omitempty"`
+}
+
+type S3Transition struct {
+	Days         int32  `json:"days"`
+	StorageClass string `json:"storage_class"`
+}
+
+// S3LifecycleRules is a list of lifecycle rules stored as a JSON text column.
+type S3LifecycleRules []S3LifecycleRule
+
+func (s S3LifecycleRules) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *S3LifecycleRules) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s S3Bucket) IsEmpty() bool {
+	return reflect.DeepEqual(s, S3Bucket{})
+}
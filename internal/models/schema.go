@@ -0,0 +1,35 @@
+package models
+
+import "reflect"
+
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeJSON     = "JSON"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// Schema is a schema registered under the subject Name.
+type Schema struct {
+	Model
+	ModuleReleaseID uint   `json:"-"`
+	Name            string `json:"name"`
+	SchemaType      string `json:"schema_type"`
+	Schema          string `gorm:"type:text" json:"schema"`
+	Compatibility   string `json:"compatibility"`
+	Revision        int    `json:"revision"`
+}
+
+func (s Schema) IsEmpty() bool {
+	return reflect.DeepEqual(s, Schema{})
+}
+
+// RegisteredSchema records the id and version the registry assigned to the
+// latest schema registered by the controller under a subject. Adopted is set
+// when the subject already had versions before the controller registered one.
+type RegisteredSchema struct {
+	Model
+	Subject  string `gorm:"uniqueIndex" json:"subject"`
+	SchemaID int    `json:"schema_id"`
+	Version  int    `json:"version"`
+	Adopted  bool   `json:"adopted"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+type SNSTopic struct {
+	Model
+	ModuleReleaseID           uint             `json:"-"`
+	Name                      string           `json:"name"`
+	Account                   string           `json:"account"`
+	Region                    string           `json:"region"`
+	Fifo                      bool             `json:"fifo"`
+	ContentBasedDeduplication bool             `json:"content_based_deduplication"`
+	DisplayName               string           `json:"display_name"`
+	EncryptionKeyID           string           `json:"encryption_key_id"`
+	Subscriptions             SNSSubscriptions `gorm:"type:text" json:"subscriptions"`
+	Tags                      StringMap        `gorm:"type:text" json:"tags"`
+	Revision                  int              `json:"revision"`
+}
+
+// SNSSubscription subscribes an SQS queue of the same account and region to
+// the topic.
+type SNSSubscription struct {
+	Queue              string `json:"queue"`
+	RawMessageDelivery bool   `json:"raw_message_delivery,omitempty"`
+	FilterPolicy       string `json:"filter_policy,omitempty"`
+}
+
+// SNSSubscriptions is a list of subscriptions stored as a JSON text column.
+type SNSSubscriptions []SNSSubscription
+
+func (s SNSSubscriptions) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *SNSSubscriptions) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s SNSTopic) IsEmpty() bool {
+	return reflect.DeepEqual(s, SNSTopic{})
+}
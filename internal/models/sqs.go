@@ -0,0 +1,27 @@
+package models
+
+import "reflect"
+
+type SQSQueue struct {
+	Model
+	ModuleReleaseID           uint      `json:"-"`
+	Name                      string    `json:"name"`
+	Account                   string    `json:"account"`
+	Region                    string    `json:"region"`
+	Fifo                      bool      `json:"fifo"`
+	ContentBasedDeduplication bool      `json:"content_based_deduplication"`
+	VisibilityTimeoutSeconds  int32     `json:"visibility_timeout_seconds"`
+	MessageRetentionSeconds   int32     `json:"message_retention_seconds"`
+	DelaySeconds              int32     `json:"delay_seconds"`
+	ReceiveWaitTimeSeconds    int32     `json:"receive_wait_time_seconds"`
+	MaxMessageSize            int32     `json:"max_message_size"`
+	EncryptionKeyID           string    `json:"encryption_key_id"`
+	DeadLetterQueue           string    `json:"dead_letter_queue"`
+	MaxReceiveCount           int32     `json:"max_receive_count"`
+	Tags                      StringMap `gorm:"type:text" json:"tags"`
+	Revision                  int       `json:"revision"`
+}
+
+func (s SQSQueue) IsEmpty() bool {
+	return reflect.DeepEqual(s, SQSQueue{})
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringMap is a map stored as a JSON text column.
+type StringMap map[string]string
+
+func (s StringMap) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *StringMap) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+// StringList is a list stored as a JSON text column.
+type StringList []string
+
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *StringList) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+// SecretReference refers to a secret of one of the secret providers, with the
+// key format of that provider.
+type SecretReference struct {
+	Provider string `json:"provider"`
+	Key      string `json:"key"`
+}
+
+// SecretReferences maps names to secret references, stored as a JSON text
+// column.
+type SecretReferences map[string]SecretReference
+
+func (s SecretReferences) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *SecretReferences) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+func scanText(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T into a JSON column", value)
+	}
+}
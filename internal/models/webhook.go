@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// Webhook is a component managed through declared HTTP calls. A call without
+// url is not set: Update falls back to Install, and nothing is called on
+// uninstall unless Uninstall is set.
+type Webhook struct {
+	Model
+	ModuleReleaseID uint        `json:"-"`
+	Name            string      `json:"name"`
+	Install         WebhookCall `gorm:"type:text" json:"install"`
+	Update          WebhookCall `gorm:"type:text" json:"update"`
+	Uninstall       WebhookCall `gorm:"type:text" json:"uninstall"`
+	Outputs         StringMap   `gorm:"type:text" json:"outputs"`
+	Revision        int         `json:"revision"`
+}
+
+// WebhookCall is a single HTTP call. HeaderSecrets are headers read from the
+// secret providers, so tokens are never stored. ExpectedStatus defaults to
+// any 2xx status and Retries to the configured number of retries for
+// idempotent methods, and to none for the others.
+type WebhookCall struct {
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	HeaderSecrets  SecretReferences  `json:"header_secrets,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	ExpectedStatus []int             `json:"expected_status,omitempty"`
+	Retries        *int              `json:"retries,omitempty"`
+}
+
+func (w WebhookCall) Value() (driver.Value, error) {
+	if w.IsEmpty() {
+		return "", nil
+	}
+	value, err := json.Marshal(w)
+	return string(value), err
+}
+
+func (w *WebhookCall) Scan(value interface{}) error {
+	data, err := scanText(value)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, w)
+}
+
+func (w WebhookCall) IsEmpty() bool {
+	return w.URL == ""
+}
+
+func (w Webhook) IsEmpty() bool {
+	return reflect.DeepEqual(w, Webhook{})
+}
+
+// WebhookResult records the last successful call made for a webhook and the
+// outputs captured from its response.
+type WebhookResult struct {
+	Model
+	WebhookName string    `gorm:"uniqueIndex" json:"webhook_name"`
+	Action      string    `json:"action"`
+	StatusCode  int       `json:"status_code"`
+	Outputs     StringMap `gorm:"type:text" json:"outputs"`
+}
@@ -0,0 +1,475 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/airflow"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+var airflowConfigColumns = []string{
+	"connections",
+	"variables",
+	"pools",
+	"import",
+}
+
+type AirflowProvider struct {
+	database        *gorm.DB
+	airflowClient   *airflow.Client
+	secretProviders map[string]SecretProviders
+}
+
+func InitAirflowProvider(db *gorm.DB, airflowClient *airflow.Client, secretProviders map[string]SecretProviders) Providers {
+	airflowProvider := &AirflowProvider{}
+	airflowProvider.database = db
+	airflowProvider.airflowClient = airflowClient
+	airflowProvider.secretProviders = secretProviders
+
+	return airflowProvider
+}
+
+func (a *AirflowProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Airflow{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (a *AirflowProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Airflow
+	if prevData != nil {
+		oldData, ok = prevData.(models.Airflow)
+		if !ok {
+			err := errors.New("conversion to airflow failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (a *AirflowProvider) InstallComponent(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	err := a.apply(context.TODO(), airflowData, models.Airflow{})
+	if err != nil && airflowData.Import {
+		// what was written is imported and stays whatever happens to the release
+		return existingComponent(err)
+	}
+	return err
+}
+
+// UpdateComponent applies the spec, then deletes the connections, variables
+// and pools of the previous revision that are no longer declared.
+func (a *AirflowProvider) UpdateComponent(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	// the stored component still holds the previous revision
+	oldInterface, err := a.GetDetail(airflowData.Name)
+	if err != nil {
+		return err
+	}
+	oldData := oldInterface.(models.Airflow)
+
+	err = a.apply(context.TODO(), airflowData, oldData)
+	if err != nil {
+		return err
+	}
+	if airflowData.Import {
+		return nil
+	}
+
+	removed := models.Airflow{}
+	connections := map[string]bool{}
+	for _, connection := range airflowData.Connections {
+		connections[connection.ID] = true
+	}
+	for _, connection := range oldData.Connections {
+		if !connections[connection.ID] {
+			removed.Connections = append(removed.Connections, connection)
+		}
+	}
+	variables := map[string]bool{}
+	for _, variable := range airflowData.Variables {
+		variables[variable.Key] = true
+	}
+	for _, variable := range oldData.Variables {
+		if !variables[variable.Key] {
+			removed.Variables = append(removed.Variables, variable)
+		}
+	}
+	pools := map[string]bool{}
+	for _, pool := range airflowData.Pools {
+		pools[pool.Name] = true
+	}
+	for _, pool := range oldData.Pools {
+		if !pools[pool.Name] {
+			removed.Pools = append(removed.Pools, pool)
+		}
+	}
+	return a.remove(context.TODO(), removed)
+}
+
+func (a *AirflowProvider) UninstallComponent(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	// imported connections, variables and pools are only forgotten
+	if airflowData.Import {
+		return nil
+	}
+	return a.remove(context.TODO(), airflowData)
+}
+
+func (a *AirflowProvider) GetStatus(airflowInterface interface{}) (responses.ComponentStatus, error) {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: airflowData.Name,
+	}
+
+	detail := responses.AirflowStatus{}
+	for _, connection := range airflowData.Connections {
+		_, err := a.airflowClient.GetConnection(context.TODO(), connection.ID)
+		if errors.Is(err, airflow.ErrNotFound) {
+			detail.MissingConnections = append(detail.MissingConnections, connection.ID)
+			continue
+		}
+		if err != nil {
+			return status, err
+		}
+	}
+	for _, variable := range airflowData.Variables {
+		_, err := a.airflowClient.GetVariable(context.TODO(), variable.Key)
+		if errors.Is(err, airflow.ErrNotFound) {
+			detail.MissingVariables = append(detail.MissingVariables, variable.Key)
+			continue
+		}
+		if err != nil {
+			return status, err
+		}
+	}
+	for _, pool := range airflowData.Pools {
+		current, err := a.airflowClient.GetPool(context.TODO(), pool.Name)
+		if errors.Is(err, airflow.ErrNotFound) {
+			detail.MissingPools = append(detail.MissingPools, pool.Name)
+			continue
+		}
+		if err != nil {
+			return status, err
+		}
+		detail.Pools = append(detail.Pools, responses.AirflowPoolStatus{
+			Name:         current.Name,
+			Slots:        current.Slots,
+			OpenSlots:    current.OpenSlots,
+			RunningSlots: current.RunningSlots,
+			QueuedSlots:  current.QueuedSlots,
+		})
+	}
+
+	status.Detail = detail
+	if len(detail.MissingConnections)+len(detail.MissingVariables)+len(detail.MissingPools) > 0 {
+		status.Status = "missing"
+		status.Message = "some connections, variables or pools do not exist in airflow"
+		return status, nil
+	}
+	status.Healthy = true
+	status.Status = "available"
+	return status, nil
+}
+
+func (a *AirflowProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := a.database.Model(&models.Airflow{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (a *AirflowProvider) Add(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	result := a.database.Create(&airflowData)
+	return result.Error
+}
+
+func (a *AirflowProvider) Remove(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	result := a.database.Delete(&models.Airflow{}, "name = ?", airflowData.Name)
+	return result.Error
+}
+
+func (a *AirflowProvider) Update(airflowInterface interface{}) error {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return err
+	}
+
+	return updateByName(a.database, &airflowData, airflowData.Name, airflowConfigColumns)
+}
+
+func (a *AirflowProvider) GetDetail(releaseName string) (interface{}, error) {
+	var airflowData models.Airflow
+	result := a.database.Where("name = ?", releaseName).First(&airflowData)
+	return airflowData, result.Error
+}
+
+func (a *AirflowProvider) GetDetailFromComponent(airflowInterface interface{}) (interface{}, error) {
+	airflowData, ok := airflowInterface.(models.Airflow)
+	if !ok {
+		err := errors.New("conversion to airflow failed")
+		return nil, err
+	}
+
+	return a.GetDetail(airflowData.Name)
+}
+
+func (a *AirflowProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var airflowList []models.Airflow
+	result := a.database.Where("module_release_id = ?", ModuleReleaseID).Find(&airflowList)
+
+	var airflowInterface []interface{} = make([]interface{}, len(airflowList))
+	for i, v := range airflowList {
+		airflowInterface[i] = v
+	}
+
+	return airflowInterface, result.Error
+}
+
+// apply creates or replaces every declared connection, variable and pool,
+// reading their secrets from the secret providers first so nothing is
+// written when one of them is missing. Unless the component is imported,
+// those that exist without being in the previous revision are refused first.
+func (a *AirflowProvider) apply(ctx context.Context, airflowData models.Airflow, previous models.Airflow) error {
+	var connections []airflow.Connection
+	for _, connection := range airflowData.Connections {
+		if connection.ID == "" || connection.Type == "" {
+			return errors.New("airflow connections need an id and a type")
+		}
+		airflowConnection := airflow.Connection{
+			ConnectionID: connection.ID,
+			ConnType:     connection.Type,
+			Host:         connection.Host,
+			Login:        connection.Login,
+			Schema:       connection.Schema,
+		}
+		if connection.Port != 0 {
+			port := connection.Port
+			airflowConnection.Port = &port
+		}
+		if connection.PasswordSecret != nil {
+			password, err := resolveSecret(a.secretProviders, *connection.PasswordSecret)
+			if err != nil {
+				return fmt.Errorf("password of connection %s: %w", connection.ID, err)
+			}
+			airflowConnection.Password = password
+		}
+		extra, err := a.connectionExtra(connection)
+		if err != nil {
+			return err
+		}
+		airflowConnection.Extra = extra
+		connections = append(connections, airflowConnection)
+	}
+
+	var variables []airflow.Variable
+	for _, variable := range airflowData.Variables {
+		if variable.Key == "" {
+			return errors.New("airflow variables need a key")
+		}
+		value := variable.Value
+		if variable.ValueSecret != nil {
+			secret, err := resolveSecret(a.secretProviders, *variable.ValueSecret)
+			if err != nil {
+				return fmt.Errorf("value of variable %s: %w", variable.Key, err)
+			}
+			value = secret
+		}
+		variables = append(variables, airflow.Variable{
+			Key:   variable.Key,
+			Value: value,
+		})
+	}
+
+	if !airflowData.Import {
+		err := a.refuseExisting(ctx, airflowData, previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, connection := range connections {
+		err := a.airflowClient.PutConnection(ctx, connection)
+		if err != nil {
+			return err
+		}
+	}
+	for _, variable := range variables {
+		err := a.airflowClient.PutVariable(ctx, variable)
+		if err != nil {
+			return err
+		}
+	}
+	for _, pool := range airflowData.Pools {
+		err := a.airflowClient.PutPool(ctx, airflow.Pool{
+			Name:  pool.Name,
+			Slots: pool.Slots,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectionExtra encodes the extra of the connection with its secrets, or
+// returns "" when it has none.
+func (a *AirflowProvider) connectionExtra(connection models.AirflowConnection) (string, error) {
+	if len(connection.Extra) == 0 && len(connection.ExtraSecrets) == 0 {
+		return "", nil
+	}
+	extra := map[string]interface{}{}
+	for key, value := range connection.Extra {
+		extra[key] = value
+	}
+	for key, reference := range connection.ExtraSecrets {
+		if _, ok := extra[key]; ok {
+			return "", fmt.Errorf("extra %s of connection %s is both a value and a secret", key, connection.ID)
+		}
+		value, err := resolveSecret(a.secretProviders, reference)
+		if err != nil {
+			return "", fmt.Errorf("extra %s of connection %s: %w", key, connection.ID, err)
+		}
+		extra[key] = value
+	}
+	encoded, err := json.Marshal(extra)
+	return string(encoded), err
+}
+
+// refuseExisting fails when a connection, variable or pool that is not in the
+// previous revision already exists in Airflow.
+func (a *AirflowProvider) refuseExisting(ctx context.Context, airflowData models.Airflow, previous models.Airflow) error {
+	connections := map[string]bool{}
+	for _, connection := range previous.Connections {
+		connections[connection.ID] = true
+	}
+	for _, connection := range airflowData.Connections {
+		if connections[connection.ID] {
+			continue
+		}
+		_, err := a.airflowClient.GetConnection(ctx, connection.ID)
+		if err == nil {
+			return existingComponent(fmt.Errorf("airflow connection %s already exists, set import to manage it", connection.ID))
+		}
+		if !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+
+	variables := map[string]bool{}
+	for _, variable := range previous.Variables {
+		variables[variable.Key] = true
+	}
+	for _, variable := range airflowData.Variables {
+		if variables[variable.Key] {
+			continue
+		}
+		_, err := a.airflowClient.GetVariable(ctx, variable.Key)
+		if err == nil {
+			return existingComponent(fmt.Errorf("airflow variable %s already exists, set import to manage it", variable.Key))
+		}
+		if !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+
+	pools := map[string]bool{}
+	for _, pool := range previous.Pools {
+		pools[pool.Name] = true
+	}
+	for _, pool := range airflowData.Pools {
+		if pools[pool.Name] {
+			continue
+		}
+		_, err := a.airflowClient.GetPool(ctx, pool.Name)
+		if err == nil {
+			return existingComponent(fmt.Errorf("airflow pool %s already exists, set import to manage it", pool.Name))
+		}
+		if !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AirflowProvider) remove(ctx context.Context, airflowData models.Airflow) error {
+	for _, connection := range airflowData.Connections {
+		err := a.airflowClient.DeleteConnection(ctx, connection.ID)
+		if err != nil && !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+	for _, variable := range airflowData.Variables {
+		err := a.airflowClient.DeleteVariable(ctx, variable.Key)
+		if err != nil && !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+	for _, pool := range airflowData.Pools {
+		err := a.airflowClient.DeletePool(ctx, pool.Name)
+		if err != nil && !errors.Is(err, airflow.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
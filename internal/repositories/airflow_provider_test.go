@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+// mapSecretProvider serves the secrets of a map.
+type mapSecretProvider map[string]string
+
+func (m mapSecretProvider) GetSecret(key string) (string, error) {
+	value, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", key)
+	}
+	return value, nil
+}
+
+func TestConnectionExtra(t *testing.T) {
+	provider := &AirflowProvider{secretProviders: map[string]SecretProviders{
+		"vault": mapSecretProvider{"airflow/gcp:keyfile": `{"type": "service_account"}`},
+	}}
+	keyfile := models.SecretReference{Provider: "vault", Key: "airflow/gcp:keyfile"}
+
+	tests := []struct {
+		name       string
+		connection models.AirflowConnection
+		want       string
+		wantErr    bool
+	}{
+		{name: "none", connection: models.AirflowConnection{ID: "gcp"}},
+		{name: "values", connection: models.AirflowConnection{ID: "gcp", Extra: map[string]interface{}{"project": "data", "retries": 3}}, want: `{"project":"data","retries":3}`},
+		{
+			name: "values and secrets",
+			connection: models.AirflowConnection{
+				ID:           "gcp",
+				Extra:        map[string]interface{}{"project": "data"},
+				ExtraSecrets: models.SecretReferences{"keyfile_dict": keyfile},
+			},
+			want: `{"keyfile_dict":"{\"type\": \"service_account\"}","project":"data"}`,
+		},
+		{
+			name: "key both a value and a secret",
+			connection: models.AirflowConnection{
+				ID:           "gcp",
+				Extra:        map[string]interface{}{"keyfile_dict": "plain"},
+				ExtraSecrets: models.SecretReferences{"keyfile_dict": keyfile},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "missing secret",
+			connection: models.AirflowConnection{ID: "gcp", ExtraSecrets: models.SecretReferences{"token": {Provider: "vault", Key: "airflow/gcp:token"}}},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown provider",
+			connection: models.AirflowConnection{ID: "gcp", ExtraSecrets: models.SecretReferences{"token": {Provider: "ssm", Key: "token"}}},
+			wantErr:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := provider.connectionExtra(test.connection)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	helm "github.com/mittwald/go-helm-client"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+// crdFieldManager owns the fields of the chart CRDs applied on upgrade, apart
+// from the manifests applied by the controller.
+const crdFieldManager = "warehouse-controller-crds"
+
+var chartOptionColumns = []string{
+	"option_timeout",
+	"option_wait",
+	"option_wait_for_jobs",
+	"option_atomic",
+	"option_create_namespace",
+	"option_skip_crds",
+	"option_force",
+	"option_disable_hooks",
+	"option_max_history",
+	"option_cleanup_on_fail",
+	"option_description",
+	"option_run_tests",
+}
+
+// chartOptions holds the install options of a chart release once the server
+// side defaults and upper bounds have been applied.
+type chartOptions struct {
+	models.ChartOptions
+	timeout    time.Duration
+	wait       bool
+	maxHistory int
+}
+
+func (h *ChartProvider) resolveOptions(options models.ChartOptions) (chartOptions, error) {
+	resolved := chartOptions{
+		ChartOptions: options,
+		timeout:      h.helmConfig.DefaultTimeout,
+		wait:         h.helmConfig.DefaultWait,
+		maxHistory:   h.helmConfig.DefaultMaxHistory,
+	}
+
+	if options.Timeout != "" {
+		timeout, err := time.ParseDuration(options.Timeout)
+		if err != nil {
+			return resolved, err
+		}
+		resolved.timeout = timeout
+	}
+	if h.helmConfig.MaxTimeout > 0 && resolved.timeout > h.helmConfig.MaxTimeout {
+		return resolved, fmt.Errorf("timeout %s exceeds the maximum of %s", resolved.timeout, h.helmConfig.MaxTimeout)
+	}
+
+	if options.Wait != nil {
+		resolved.wait = *options.Wait
+	}
+
+	if options.MaxHistory != 0 {
+		resolved.maxHistory = options.MaxHistory
+	}
+	if h.helmConfig.MaxHistoryLimit > 0 && resolved.maxHistory > h.helmConfig.MaxHistoryLimit {
+		return resolved, fmt.Errorf("maxHistory %d exceeds the maximum of %d", resolved.maxHistory, h.helmConfig.MaxHistoryLimit)
+	}
+
+	return resolved, nil
+}
+
+func (h *ChartProvider) getHelmClient(namespace string) (*helm.HelmClient, error) {
+	client, ok := h.helmClient[namespace]
+	if !ok {
+		err := errors.New("unknown namespace")
+		return nil, err
+	}
+	helmClient, ok := client.(*helm.HelmClient)
+	if !ok {
+		err := errors.New("helm client does not expose its action configuration")
+		return nil, err
+	}
+	return helmClient, nil
+}
+
+const (
+	uploadedChartScheme = "uploaded://"
+	localChartScheme    = "file://"
+)
+
+// isRepoChart tells whether name references a chart of the configured
+// repository, the only charts that need the repository index.
+func isRepoChart(name string) bool {
+	return !strings.HasPrefix(name, uploadedChartScheme) && !strings.HasPrefix(name, localChartScheme)
+}
+
+// loadChart loads the chart referenced by name. Besides charts from the
+// configured repository, name can reference an uploaded chart archive as
+// uploaded://name/version or, when local charts are allowed, a chart directory
+// or archive on the controller as file://path.
+func (h *ChartProvider) loadChart(client *helm.HelmClient, chartPathOptions *action.ChartPathOptions, name string) (*chart.Chart, error) {
+	if strings.HasPrefix(name, uploadedChartScheme) {
+		return h.loadUploadedChart(strings.TrimPrefix(name, uploadedChartScheme), chartPathOptions.Version)
+	}
+
+	if strings.HasPrefix(name, localChartScheme) {
+		if !h.helmConfig.AllowLocalCharts {
+			err := errors.New("local charts are not allowed")
+			return nil, err
+		}
+		return loader.Load(strings.TrimPrefix(name, localChartScheme))
+	}
+
+	if chartPathOptions.Version == "" {
+		chartPathOptions.Version = ">0.0.0-0"
+	}
+
+	chartPath, err := chartPathOptions.LocateChart(name, client.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.Load(chartPath)
+}
+
+func (h *ChartProvider) loadUploadedChart(reference string, version string) (*chart.Chart, error) {
+	reference = strings.TrimSuffix(reference, "/")
+	name := reference
+	if i := strings.Index(reference, "/"); i >= 0 {
+		name = reference[:i]
+		version = reference[i+1:]
+	}
+
+	archive, err := h.chartArchiveRepository.GetChartArchive(name, version)
+	if err != nil {
+		return nil, fmt.Errorf("uploaded chart %s: %w", reference, err)
+	}
+
+	return loader.LoadArchive(bytes.NewReader(archive.Content))
+}
+
+func (h *ChartProvider) installOrUpgrade(ctx context.Context, chartRelease models.ChartRelease) (*release.Release, error) {
+	client, err := h.getHelmClient(chartRelease.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := h.resolveOptions(chartRelease.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := chartutil.ReadValues([]byte(chartRelease.Values))
+	if err != nil {
+		return nil, err
+	}
+
+	history := action.NewHistory(client.ActionConfig)
+	history.Max = 1
+	_, err = history.Run(chartRelease.ReleaseName)
+	if err == driver.ErrReleaseNotFound {
+		return h.install(ctx, client, chartRelease, options, values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h.upgrade(ctx, client, chartRelease, options, values)
+}
+
+func (h *ChartProvider) install(ctx context.Context, client *helm.HelmClient, chartRelease models.ChartRelease, options chartOptions, values chartutil.Values) (*release.Release, error) {
+	install := action.NewInstall(client.ActionConfig)
+	install.ReleaseName = chartRelease.ReleaseName
+	install.Namespace = chartRelease.Namespace
+	install.Version = chartRelease.Version
+	install.Timeout = options.timeout
+	install.Wait = options.wait
+	install.WaitForJobs = options.WaitForJobs
+	install.Atomic = options.Atomic
+	install.CreateNamespace = options.CreateNamespace
+	install.SkipCRDs = options.SkipCRDs
+	install.DisableHooks = options.DisableHooks
+	install.Description = options.Description
+
+	helmChart, err := h.loadChart(client, &install.ChartPathOptions, chartRelease.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return install.RunWithContext(ctx, helmChart, values)
+}
+
+func (h *ChartProvider) upgrade(ctx context.Context, client *helm.HelmClient, chartRelease models.ChartRelease, options chartOptions, values chartutil.Values) (*release.Release, error) {
+	upgrade := action.NewUpgrade(client.ActionConfig)
+	upgrade.Namespace = chartRelease.Namespace
+	upgrade.Version = chartRelease.Version
+	upgrade.Timeout = options.timeout
+	upgrade.Wait = options.wait
+	upgrade.WaitForJobs = options.WaitForJobs
+	upgrade.Atomic = options.Atomic
+	upgrade.SkipCRDs = options.SkipCRDs
+	upgrade.Force = options.Force
+	upgrade.DisableHooks = options.DisableHooks
+	upgrade.MaxHistory = options.maxHistory
+	upgrade.CleanupOnFail = options.CleanupOnFail
+	upgrade.Description = options.Description
+
+	helmChart, err := h.loadChart(client, &upgrade.ChartPathOptions, chartRelease.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.SkipCRDs {
+		err = h.upgradeCRDs(client, helmChart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return upgrade.RunWithContext(ctx, chartRelease.ReleaseName, helmChart, values)
+}
+
+// upgradeCRDs applies the CRDs shipped in the chart's crds directory, which
+// helm itself only creates on the first install. They are applied server side,
+// taking over the fields the chart sets and creating the missing ones.
+func (h *ChartProvider) upgradeCRDs(client *helm.HelmClient, helmChart *chart.Chart) error {
+	force := true
+	for _, crd := range helmChart.CRDObjects() {
+		resources, err := client.ActionConfig.KubeClient.Build(bytes.NewBuffer(crd.File.Data), false)
+		if err != nil {
+			return err
+		}
+		for _, info := range resources {
+			data, err := json.Marshal(info.Object)
+			if err != nil {
+				return err
+			}
+			_, err = resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.ApplyPatchType, data, &metav1.PatchOptions{
+				FieldManager: crdFieldManager,
+				Force:        &force,
+			})
+			if err != nil {
+				return fmt.Errorf("applying crd %s: %w", info.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (h *ChartProvider) rollback(chartRelease models.ChartRelease, revision int) error {
+	client, err := h.getHelmClient(chartRelease.Namespace)
+	if err != nil {
+		return err
+	}
+
+	options, err := h.resolveOptions(chartRelease.Options)
+	if err != nil {
+		return err
+	}
+
+	rollback := action.NewRollback(client.ActionConfig)
+	rollback.Version = revision
+	rollback.Timeout = options.timeout
+	rollback.Wait = options.wait
+	rollback.WaitForJobs = options.WaitForJobs
+	rollback.Force = options.Force
+	rollback.DisableHooks = options.DisableHooks
+	rollback.MaxHistory = options.maxHistory
+	rollback.CleanupOnFail = options.CleanupOnFail
+
+	return rollback.Run(chartRelease.ReleaseName)
+}
+
+// template renders the chart release with a dry-run install. Unless validate is
+// set the chart is rendered client side only, otherwise the manifests are
+// rendered with the capabilities of the target cluster and validated against it.
+func (h *ChartProvider) template(chartRelease models.ChartRelease, validate bool) (string, error) {
+	client, err := h.getHelmClient(chartRelease.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	options, err := h.resolveOptions(chartRelease.Options)
+	if err != nil {
+		return "", err
+	}
+
+	values, err := chartutil.ReadValues([]byte(chartRelease.Values))
+	if err != nil {
+		return "", err
+	}
+
+	install := action.NewInstall(client.ActionConfig)
+	install.DryRun = true
+	install.Replace = true
+	install.ClientOnly = !validate
+	install.IncludeCRDs = true
+	install.ReleaseName = chartRelease.ReleaseName
+	install.Namespace = chartRelease.Namespace
+	install.Version = chartRelease.Version
+	install.SkipCRDs = options.SkipCRDs
+	install.DisableHooks = options.DisableHooks
+
+	helmChart, err := h.loadChart(client, &install.ChartPathOptions, chartRelease.Name)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := install.Run(helmChart, values)
+	if err != nil {
+		return "", err
+	}
+
+	var manifests bytes.Buffer
+	fmt.Fprintln(&manifests, strings.TrimSpace(rendered.Manifest))
+	if !install.DisableHooks {
+		for _, hook := range rendered.Hooks {
+			fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+		}
+	}
+	return manifests.String(), nil
+}
+
+// runTests runs the test hooks of the release and stores the outcome and logs
+// of every test hook. A failing test is returned as an error.
+func (h *ChartProvider) runTests(chartRelease models.ChartRelease) error {
+	client, err := h.getHelmClient(chartRelease.Namespace)
+	if err != nil {
+		return err
+	}
+
+	options, err := h.resolveOptions(chartRelease.Options)
+	if err != nil {
+		return err
+	}
+
+	test := action.NewReleaseTesting(client.ActionConfig)
+	test.Namespace = chartRelease.Namespace
+	test.Timeout = options.timeout
+
+	tested, testErr := test.Run(chartRelease.ReleaseName)
+	if tested == nil {
+		return testErr
+	}
+
+	results, err := h.collectTestResults(client, tested)
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		result := h.database.Create(&results)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if testErr != nil {
+		return fmt.Errorf("helm test failed for release %s: %w", chartRelease.ReleaseName, testErr)
+	}
+	return nil
+}
+
+func (h *ChartProvider) collectTestResults(client *helm.HelmClient, tested *release.Release) ([]models.ChartTestResult, error) {
+	clientSet, err := client.ActionConfig.KubernetesClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.ChartTestResult
+	for _, hook := range tested.Hooks {
+		if !isTestHook(hook) {
+			continue
+		}
+
+		result := models.ChartTestResult{
+			ReleaseName: tested.Name,
+			Namespace:   tested.Namespace,
+			Revision:    tested.Version,
+			Name:        hook.Name,
+			Phase:       hook.LastRun.Phase.String(),
+			StartedAt:   hook.LastRun.StartedAt.Time,
+			CompletedAt: hook.LastRun.CompletedAt.Time,
+		}
+
+		logs, err := hookLogs(context.Background(), clientSet, tested.Namespace, hook)
+		if err != nil {
+			result.Logs = fmt.Sprintf("unable to get pod logs: %s", err.Error())
+		} else {
+			result.Logs = logs
+		}
+
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// hookLogs returns the logs of the pods run by a test hook, the hook pod itself
+// or the pods of a Job hook, found by their job-name label. Pods removed by the
+// hook delete policy have no logs left and are skipped.
+func hookLogs(ctx context.Context, clientSet kubernetes.Interface, namespace string, hook *release.Hook) (string, error) {
+	var pods []v1.Pod
+	if hook.Kind == "Job" {
+		list, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + hook.Name})
+		if err != nil {
+			return "", err
+		}
+		pods = list.Items
+		sort.Slice(pods, func(i, j int) bool {
+			return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+		})
+	} else {
+		pod, err := clientSet.CoreV1().Pods(namespace).Get(ctx, hook.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		pods = append(pods, *pod)
+	}
+
+	var logs strings.Builder
+	for _, pod := range pods {
+		podLogs, err := clientSet.CoreV1().Pods(namespace).GetLogs(pod.Name, &v1.PodLogOptions{}).DoRaw(ctx)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(pods) > 1 {
+			fmt.Fprintf(&logs, "==> %s <==\n", pod.Name)
+		}
+		logs.Write(podLogs)
+	}
+	return logs.String(), nil
+}
+
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHookLogs(t *testing.T) {
+	tests := []struct {
+		name string
+		hook release.Hook
+		pods []corev1.Pod
+		want string
+	}{
+		{
+			name: "pod hook",
+			hook: release.Hook{Kind: "Pod", Name: "test-connection"},
+			pods: []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "test-connection"}}},
+			want: "fake logs",
+		},
+		{
+			name: "deleted pod hook",
+			hook: release.Hook{Kind: "Pod", Name: "test-connection"},
+		},
+		{
+			name: "job hook",
+			hook: release.Hook{Kind: "Job", Name: "test-job"},
+			pods: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-job-b", Labels: map[string]string{"job-name": "test-job"}, CreationTimestamp: metav1.Unix(2, 0)}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-job-a", Labels: map[string]string{"job-name": "test-job"}, CreationTimestamp: metav1.Unix(1, 0)}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"job-name": "other"}}},
+			},
+			want: "==> test-job-a <==\nfake logs==> test-job-b <==\nfake logs",
+		},
+		{
+			name: "job hook without pods",
+			hook: release.Hook{Kind: "Job", Name: "test-job"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			for _, pod := range test.pods {
+				pod.Namespace = "data"
+				_, err := clientset.CoreV1().Pods("data").Create(context.TODO(), &pod, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := hookLogs(context.TODO(), clientset, "data", &test.hook)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"gorm.io/gorm"
+)
+
+// IChartArchiveRepository stores uploaded chart archives. Charts stored here
+// are referenced from chart releases as uploaded://name/version.
+type IChartArchiveRepository interface {
+	InsertChartArchive(models.ChartArchive) error
+	GetChartArchive(string, string) (models.ChartArchive, error)
+	GetAllChartArchive() ([]models.ChartArchive, error)
+	DeleteChartArchive(string, string) error
+}
+
+type ChartArchiveRepository struct {
+	database *gorm.DB
+}
+
+func InitChartArchiveRepository(database *gorm.DB) IChartArchiveRepository {
+	chartArchiveRepository := &ChartArchiveRepository{}
+	chartArchiveRepository.database = database
+	return chartArchiveRepository
+}
+
+func (c ChartArchiveRepository) InsertChartArchive(archive models.ChartArchive) error {
+	result := c.database.Create(&archive)
+	return result.Error
+}
+
+func (c ChartArchiveRepository) GetChartArchive(name string, version string) (models.ChartArchive, error) {
+	var archive models.ChartArchive
+	var result *gorm.DB
+	if version != "" {
+		result = c.database.Where("name = ? AND version = ?", name, version).First(&archive)
+	} else {
+		result = c.database.Order("created_at desc").Where("name = ?", name).First(&archive)
+	}
+	return archive, result.Error
+}
+
+func (c ChartArchiveRepository) GetAllChartArchive() ([]models.ChartArchive, error) {
+	var archives []models.ChartArchive
+	result := c.database.Omit("content").Order("name, created_at desc").Find(&archives)
+	return archives, result.Error
+}
+
+func (c ChartArchiveRepository) DeleteChartArchive(name string, version string) error {
+	result := c.database.Unscoped().Delete(&models.ChartArchive{}, "name = ? AND version = ?", name, version)
+	return result.Error
+}
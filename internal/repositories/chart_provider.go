@@ -4,28 +4,46 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"time"
 
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/requests"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
 	helm "github.com/mittwald/go-helm-client"
 	"gorm.io/gorm"
 	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
 )
 
+type IChartProvider interface {
+	Providers
+	Import(string, string) (models.ChartRelease, error)
+	Rollback(models.ChartRelease, int) (models.ChartRelease, error)
+	GetHistory(models.ChartRelease) ([]responses.ChartHistory, error)
+	Template(models.ChartRelease, bool) (responses.ChartManifest, error)
+	GetTestResults(string) ([]models.ChartTestResult, error)
+}
+
+const maxReleaseHistory = 256
+
 type ChartProvider struct {
 	helmClient       map[string]helm.Client
 	database         *gorm.DB
 	defaultNamespace string
 	chartRepo        repo.Entry
+	helmConfig       configs.HelmConfig
+
+	chartArchiveRepository IChartArchiveRepository
 }
 
-func InitChartProvider(helmClient map[string]helm.Client, database *gorm.DB, defaultNamespace string, chartRepo repo.Entry) Providers {
+func InitChartProvider(helmClient map[string]helm.Client, database *gorm.DB, defaultNamespace string, chartRepo repo.Entry, helmConfig configs.HelmConfig, chartArchiveRepository IChartArchiveRepository) IChartProvider {
 	chartProvider := &ChartProvider{}
 	chartProvider.helmClient = helmClient
 	chartProvider.database = database
 	chartProvider.defaultNamespace = defaultNamespace
 	chartProvider.chartRepo = chartRepo
+	chartProvider.helmConfig = helmConfig
+	chartProvider.chartArchiveRepository = chartArchiveRepository
 	return chartProvider
 }
 
@@ -78,28 +96,26 @@ func (h *ChartProvider) InstallComponent(chartInterface interface{}) error {
 		return err
 	}
 
-	chartSpec := helm.ChartSpec{
-		ReleaseName: chart.ReleaseName,
-		ChartName:   chart.Name,
-		Version:     chart.Version,
-		UpgradeCRDs: true,
-		Wait:        true,
-		Timeout:     time.Minute * 5,
-		ValuesYaml:  chart.Values,
-		Namespace:   chart.Namespace,
-	}
-
 	if _, ok := h.helmClient[chart.Namespace]; !ok {
 		err := errors.New("unknown namespace")
 		return err
 	}
 
-	if err := h.helmClient[chart.Namespace].AddOrUpdateChartRepo(h.chartRepo); err != nil {
+	if isRepoChart(chart.Name) {
+		if err := h.helmClient[chart.Namespace].AddOrUpdateChartRepo(h.chartRepo); err != nil {
+			return err
+		}
+	}
+
+	_, err := h.installOrUpgrade(context.Background(), chart)
+	if err != nil {
 		return err
 	}
 
-	_, err := h.helmClient[chart.Namespace].InstallOrUpgradeChart(context.Background(), &chartSpec)
-	return err
+	if chart.Options.RunTests {
+		return h.runTests(chart)
+	}
+	return nil
 }
 
 func (h *ChartProvider) UpdateComponent(releaseInterface interface{}) error {
@@ -149,6 +165,11 @@ func (h *ChartProvider) Remove(releaseInterface interface{}) error {
 	}
 
 	result := h.database.Delete(&models.ChartRelease{}, "release_name = ?", release.ReleaseName)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = h.database.Delete(&models.ChartTestResult{}, "release_name = ?", release.ReleaseName)
 	return result.Error
 }
 
@@ -160,6 +181,11 @@ func (h *ChartProvider) Update(releaseInterface interface{}) error {
 	}
 
 	result := h.database.Model(&release).Where("release_name = ?", release.ReleaseName).Updates(release)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = h.database.Model(&release).Where("release_name = ?", release.ReleaseName).Select(chartOptionColumns).Updates(release)
 	return result.Error
 }
 
@@ -190,3 +216,117 @@ func (h *ChartProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interfac
 
 	return chartsInterface, result.Error
 }
+
+func (h *ChartProvider) Import(releaseName string, namespace string) (models.ChartRelease, error) {
+	if namespace == "" {
+		namespace = h.defaultNamespace
+	}
+
+	if _, ok := h.helmClient[namespace]; !ok {
+		err := errors.New("unknown namespace")
+		return models.ChartRelease{}, err
+	}
+
+	release, err := h.helmClient[namespace].GetRelease(releaseName)
+	if err != nil {
+		return models.ChartRelease{}, err
+	}
+
+	values, err := h.helmClient[namespace].GetReleaseValues(releaseName, false)
+	if err != nil {
+		return models.ChartRelease{}, err
+	}
+
+	valuesYaml, err := yaml.Marshal(values)
+	if err != nil {
+		return models.ChartRelease{}, err
+	}
+
+	chart := models.ChartRelease{
+		Name:        h.chartRepo.Name + "/" + release.Chart.Metadata.Name,
+		ReleaseName: release.Name,
+		Version:     release.Chart.Metadata.Version,
+		Values:      string(valuesYaml),
+		Revision:    release.Version,
+		Namespace:   release.Namespace,
+	}
+	return chart, nil
+}
+
+func (h *ChartProvider) Rollback(chart models.ChartRelease, revision int) (models.ChartRelease, error) {
+	err := h.rollback(chart, revision)
+	if err != nil {
+		return chart, err
+	}
+
+	rolledBack, err := h.Import(chart.ReleaseName, chart.Namespace)
+	if err != nil {
+		return chart, err
+	}
+
+	chart.Version = rolledBack.Version
+	chart.Values = rolledBack.Values
+	chart.Revision = rolledBack.Revision
+	return chart, nil
+}
+
+func (h *ChartProvider) GetHistory(chart models.ChartRelease) ([]responses.ChartHistory, error) {
+	if _, ok := h.helmClient[chart.Namespace]; !ok {
+		err := errors.New("unknown namespace")
+		return nil, err
+	}
+
+	releases, err := h.helmClient[chart.Namespace].ListReleaseHistory(chart.ReleaseName, maxReleaseHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]responses.ChartHistory, len(releases))
+	for i, release := range releases {
+		history[i] = responses.ChartHistory{
+			Revision:    release.Version,
+			Updated:     release.Info.LastDeployed.Time,
+			Status:      release.Info.Status.String(),
+			Chart:       release.Chart.Metadata.Name + "-" + release.Chart.Metadata.Version,
+			AppVersion:  release.Chart.Metadata.AppVersion,
+			Description: release.Info.Description,
+		}
+	}
+	return history, nil
+}
+
+func (h *ChartProvider) Template(chart models.ChartRelease, validate bool) (responses.ChartManifest, error) {
+	if chart.Namespace == "" {
+		chart.Namespace = h.defaultNamespace
+	}
+
+	if _, ok := h.helmClient[chart.Namespace]; !ok {
+		err := errors.New("unknown namespace")
+		return responses.ChartManifest{}, err
+	}
+
+	if isRepoChart(chart.Name) {
+		if err := h.helmClient[chart.Namespace].AddOrUpdateChartRepo(h.chartRepo); err != nil {
+			return responses.ChartManifest{}, err
+		}
+	}
+
+	manifest, err := h.template(chart, validate)
+	if err != nil {
+		return responses.ChartManifest{}, err
+	}
+
+	result := responses.ChartManifest{
+		Name:        chart.Name,
+		ReleaseName: chart.ReleaseName,
+		Namespace:   chart.Namespace,
+		Manifest:    manifest,
+	}
+	return result, nil
+}
+
+func (h *ChartProvider) GetTestResults(releaseName string) ([]models.ChartTestResult, error) {
+	var results []models.ChartTestResult
+	result := h.database.Order("revision desc").Where("release_name = ?", releaseName).Find(&results)
+	return results, result.Error
+}
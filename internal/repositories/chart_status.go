@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+const maxResourceEvents = 5
+
+func (h *ChartProvider) GetStatus(releaseInterface interface{}) (responses.ComponentStatus, error) {
+	chart, ok := releaseInterface.(models.ChartRelease)
+	if !ok {
+		err := errors.New("conversion to chartRelease failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: chart.ReleaseName,
+	}
+
+	client, err := h.getHelmClient(chart.Namespace)
+	if err != nil {
+		return status, err
+	}
+
+	deployed, err := client.GetRelease(chart.ReleaseName)
+	if err != nil {
+		return status, err
+	}
+
+	built, err := client.ActionConfig.KubeClient.Build(bytes.NewBufferString(deployed.Manifest), false)
+	if err != nil {
+		return status, err
+	}
+
+	resources := make([]resourceRef, len(built))
+	for i, info := range built {
+		resources[i] = resourceRef{
+			kind:      info.Mapping.GroupVersionKind.Kind,
+			name:      info.Name,
+			namespace: info.Namespace,
+		}
+		if resources[i].namespace == "" {
+			resources[i].namespace = deployed.Namespace
+		}
+	}
+
+	clientSet, err := client.ActionConfig.KubernetesClientSet()
+	if err != nil {
+		return status, err
+	}
+
+	chartStatus, healthy, err := releaseStatus(context.Background(), clientSet, resources, deployed)
+	if err != nil {
+		return status, err
+	}
+
+	status.Healthy = healthy
+	status.Status = chartStatus.Status
+	status.Detail = chartStatus
+	return status, nil
+}
+
+// releaseStatus queries the cluster for the workloads rendered in the release
+// manifest and reports their readiness alongside the helm release status.
+func releaseStatus(ctx context.Context, clientSet kubernetes.Interface, resources []resourceRef, deployed *release.Release) (responses.ChartStatus, bool, error) {
+	status := responses.ChartStatus{
+		ReleaseName: deployed.Name,
+		Namespace:   deployed.Namespace,
+		Revision:    deployed.Version,
+		Status:      deployed.Info.Status.String(),
+		Resources:   []responses.ResourceStatus{},
+	}
+	healthy := deployed.Info.Status == release.StatusDeployed
+
+	for _, resource := range resources {
+		var resourceStatuses []responses.ResourceStatus
+		var err error
+
+		switch resource.kind {
+		case "Deployment":
+			resourceStatuses, err = deploymentStatus(ctx, clientSet, resource)
+		case "StatefulSet":
+			resourceStatuses, err = statefulSetStatus(ctx, clientSet, resource)
+		case "Job":
+			resourceStatuses, err = jobStatus(ctx, clientSet, resource)
+		case "Pod":
+			resourceStatuses, err = podStatus(ctx, clientSet, resource)
+		default:
+			continue
+		}
+		if err != nil {
+			return status, false, err
+		}
+
+		// the first entry is the workload itself, the rest are its pods
+		if !resourceStatuses[0].Ready {
+			healthy = false
+		}
+		status.Resources = append(status.Resources, resourceStatuses...)
+	}
+
+	return status, healthy, nil
+}
+
+type resourceRef struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+func deploymentStatus(ctx context.Context, clientSet kubernetes.Interface, resource resourceRef) ([]responses.ResourceStatus, error) {
+	deployment, err := clientSet.AppsV1().Deployments(resource.namespace).Get(ctx, resource.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	status := responses.ResourceStatus{
+		Kind:            resource.kind,
+		Name:            resource.name,
+		Ready:           deployment.Status.ReadyReplicas >= desired && deployment.Status.UpdatedReplicas >= desired,
+		DesiredReplicas: desired,
+		ReadyReplicas:   deployment.Status.ReadyReplicas,
+		Message:         deploymentMessage(deployment),
+	}
+	return withPods(ctx, clientSet, resource, status, deployment.Spec.Selector)
+}
+
+func deploymentMessage(deployment *appsv1.Deployment) string {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			return condition.Message
+		}
+	}
+	return ""
+}
+
+func statefulSetStatus(ctx context.Context, clientSet kubernetes.Interface, resource resourceRef) ([]responses.ResourceStatus, error) {
+	statefulSet, err := clientSet.AppsV1().StatefulSets(resource.namespace).Get(ctx, resource.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	desired := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+
+	status := responses.ResourceStatus{
+		Kind:            resource.kind,
+		Name:            resource.name,
+		Ready:           statefulSet.Status.ReadyReplicas >= desired,
+		DesiredReplicas: desired,
+		ReadyReplicas:   statefulSet.Status.ReadyReplicas,
+	}
+	return withPods(ctx, clientSet, resource, status, statefulSet.Spec.Selector)
+}
+
+func jobStatus(ctx context.Context, clientSet kubernetes.Interface, resource resourceRef) ([]responses.ResourceStatus, error) {
+	job, err := clientSet.BatchV1().Jobs(resource.namespace).Get(ctx, resource.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+
+	status := responses.ResourceStatus{
+		Kind:            resource.kind,
+		Name:            resource.name,
+		Ready:           job.Status.Succeeded >= completions,
+		DesiredReplicas: completions,
+		ReadyReplicas:   job.Status.Succeeded,
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			status.Message = condition.Message
+		}
+	}
+	return withPods(ctx, clientSet, resource, status, job.Spec.Selector)
+}
+
+func podStatus(ctx context.Context, clientSet kubernetes.Interface, resource resourceRef) ([]responses.ResourceStatus, error) {
+	pod, err := clientSet.CoreV1().Pods(resource.namespace).Get(ctx, resource.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := podResourceStatus(ctx, clientSet, *pod)
+	if err != nil {
+		return nil, err
+	}
+	return []responses.ResourceStatus{status}, nil
+}
+
+// withPods appends the status of the pods matched by selector to the status of
+// the workload owning them.
+func withPods(ctx context.Context, clientSet kubernetes.Interface, resource resourceRef, status responses.ResourceStatus, selector *metav1.LabelSelector) ([]responses.ResourceStatus, error) {
+	var err error
+	status.Events, err = resourceEvents(ctx, clientSet, resource.namespace, resource.kind, resource.name)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []responses.ResourceStatus{status}
+	if selector == nil {
+		return statuses, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := clientSet.CoreV1().Pods(resource.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		podStatus, err := podResourceStatus(ctx, clientSet, pod)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, podStatus)
+	}
+	return statuses, nil
+}
+
+func podResourceStatus(ctx context.Context, clientSet kubernetes.Interface, pod v1.Pod) (responses.ResourceStatus, error) {
+	status := responses.ResourceStatus{
+		Kind:    "Pod",
+		Name:    pod.Name,
+		Ready:   pod.Status.Phase == v1.PodSucceeded,
+		Message: string(pod.Status.Phase),
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+			status.Ready = true
+		}
+	}
+
+	for _, container := range pod.Status.ContainerStatuses {
+		status.Restarts += container.RestartCount
+		if container.State.Waiting != nil && container.State.Waiting.Reason != "" {
+			status.Message = fmt.Sprintf("%s: %s", container.Name, container.State.Waiting.Reason)
+		}
+	}
+
+	var err error
+	status.Events, err = resourceEvents(ctx, clientSet, pod.Namespace, "Pod", pod.Name)
+	return status, err
+}
+
+// resourceEvents returns the latest events recorded for the given object.
+func resourceEvents(ctx context.Context, clientSet kubernetes.Interface, namespace string, kind string, name string) ([]responses.ResourceEvent, error) {
+	selector := fields.Set{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}.AsSelector().String()
+
+	events, err := clientSet.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	items := events.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].LastTimestamp.Before(&items[j].LastTimestamp)
+	})
+	if len(items) > maxResourceEvents {
+		items = items[len(items)-maxResourceEvents:]
+	}
+
+	result := make([]responses.ResourceEvent, len(items))
+	for i, event := range items {
+		result[i] = responses.ResourceEvent{
+			Type:          event.Type,
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Count:         event.Count,
+			LastTimestamp: event.LastTimestamp.Time,
+		}
+	}
+	return result, nil
+}
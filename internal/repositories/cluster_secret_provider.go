@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ClusterSecretProvider reads the keys of Kubernetes Secrets in the available
+// namespaces.
+type ClusterSecretProvider struct {
+	clientset          kubernetes.Interface
+	availableNamespace map[string]bool
+}
+
+func InitClusterSecretProvider(restConfig *rest.Config, availableNamespace []string) (SecretProviders, error) {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSecretProvider := &ClusterSecretProvider{}
+	clusterSecretProvider.clientset = clientset
+	clusterSecretProvider.availableNamespace = map[string]bool{}
+	for _, namespace := range availableNamespace {
+		clusterSecretProvider.availableNamespace[namespace] = true
+	}
+	return clusterSecretProvider, nil
+}
+
+// GetSecret reads namespace/name:key.
+func (c ClusterSecretProvider) GetSecret(key string) (string, error) {
+	secretKV := strings.SplitN(key, ":", 2)
+	if len(secretKV) != 2 {
+		return "", fmt.Errorf("key %s parsing error", key)
+	}
+	namespaceName := strings.SplitN(secretKV[0], "/", 2)
+	if len(namespaceName) != 2 || namespaceName[0] == "" || namespaceName[1] == "" {
+		return "", fmt.Errorf("key %s parsing error", key)
+	}
+	if !c.availableNamespace[namespaceName[0]] {
+		return "", fmt.Errorf("unknown namespace %s for secret %s", namespaceName[0], namespaceName[1])
+	}
+
+	secret, err := c.clientset.CoreV1().Secrets(namespaceName[0]).Get(context.TODO(), namespaceName[1], metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[secretKV[1]]
+	if !ok {
+		return "", fmt.Errorf("key %s does not exist", secretKV[1])
+	}
+	return string(value), nil
+}
@@ -0,0 +1,363 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/kafkaconnect"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+// Kafka Connect connector and task states
+const (
+	connectorStateRunning = "RUNNING"
+	connectorStatePaused  = "PAUSED"
+	connectorStateFailed  = "FAILED"
+)
+
+var connectorConfigColumns = []string{
+	"config",
+	"secrets",
+	"paused",
+	"import",
+}
+
+type ConnectorProvider struct {
+	database        *gorm.DB
+	connectClient   *kafkaconnect.Client
+	connectConfig   configs.KafkaConnectConfig
+	secretProviders map[string]SecretProviders
+}
+
+func InitConnectorProvider(db *gorm.DB, connectClient *kafkaconnect.Client, connectConfig configs.KafkaConnectConfig, secretProviders map[string]SecretProviders) Providers {
+	connectorProvider := &ConnectorProvider{}
+	connectorProvider.database = db
+	connectorProvider.connectClient = connectClient
+	connectorProvider.connectConfig = connectConfig
+	connectorProvider.secretProviders = secretProviders
+
+	return connectorProvider
+}
+
+func (c *ConnectorProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Connector{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (c *ConnectorProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Connector
+	if prevData != nil {
+		oldData, ok = prevData.(models.Connector)
+		if !ok {
+			err := errors.New("conversion to connector failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+// InstallComponent refuses a connector that already exists, unless it is
+// imported, before applying its config.
+func (c *ConnectorProvider) InstallComponent(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	_, err := c.connectClient.GetStatus(context.TODO(), connector.Name)
+	if err == nil && !connector.Import {
+		return existingComponent(fmt.Errorf("connector %s already exists, set import to manage it", connector.Name))
+	}
+	if err != nil && !errors.Is(err, kafkaconnect.ErrNotFound) {
+		return err
+	}
+	existing := err == nil
+
+	err = c.apply(context.TODO(), connector)
+	if err != nil && existing {
+		// the imported connector stays whatever happens to the release
+		return existingComponent(err)
+	}
+	return err
+}
+
+func (c *ConnectorProvider) UpdateComponent(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	return c.apply(context.TODO(), connector)
+}
+
+func (c *ConnectorProvider) UninstallComponent(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	// imported connectors were there before the controller and outlive it
+	if connector.Import {
+		return nil
+	}
+	err := c.connectClient.Delete(context.TODO(), connector.Name)
+	if err != nil && !errors.Is(err, kafkaconnect.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (c *ConnectorProvider) GetStatus(connectorInterface interface{}) (responses.ComponentStatus, error) {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: connector.Name,
+	}
+
+	connectorStatus, err := c.connectClient.GetStatus(context.TODO(), connector.Name)
+	if err != nil {
+		return status, err
+	}
+
+	detail := responses.ConnectorStatus{
+		Name:   connector.Name,
+		State:  connectorStatus.Connector.State,
+		Worker: connectorStatus.Connector.WorkerID,
+	}
+	for _, task := range connectorStatus.Tasks {
+		detail.Tasks = append(detail.Tasks, responses.ConnectorTaskStatus{
+			ID:     task.ID,
+			State:  task.State,
+			Worker: task.WorkerID,
+			Trace:  task.Trace,
+		})
+	}
+
+	status.Detail = detail
+	status.Status = connectorStatus.Connector.State
+	err = checkConnectorState(connectorStatus, expectedConnectorState(connector))
+	if err != nil {
+		status.Message = err.Error()
+		return status, nil
+	}
+	status.Healthy = true
+	return status, nil
+}
+
+func (c *ConnectorProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := c.database.Model(&models.Connector{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (c *ConnectorProvider) Add(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	result := c.database.Create(&connector)
+	return result.Error
+}
+
+func (c *ConnectorProvider) Remove(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	result := c.database.Delete(&models.Connector{}, "name = ?", connector.Name)
+	return result.Error
+}
+
+func (c *ConnectorProvider) Update(connectorInterface interface{}) error {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return err
+	}
+
+	return updateByName(c.database, &connector, connector.Name, connectorConfigColumns)
+}
+
+func (c *ConnectorProvider) GetDetail(releaseName string) (interface{}, error) {
+	var connector models.Connector
+	result := c.database.Where("name = ?", releaseName).First(&connector)
+	return connector, result.Error
+}
+
+func (c *ConnectorProvider) GetDetailFromComponent(connectorInterface interface{}) (interface{}, error) {
+	connector, ok := connectorInterface.(models.Connector)
+	if !ok {
+		err := errors.New("conversion to connector failed")
+		return nil, err
+	}
+
+	return c.GetDetail(connector.Name)
+}
+
+func (c *ConnectorProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var connectors []models.Connector
+	result := c.database.Where("module_release_id = ?", ModuleReleaseID).Find(&connectors)
+
+	var connectorInterface []interface{} = make([]interface{}, len(connectors))
+	for i, v := range connectors {
+		connectorInterface[i] = v
+	}
+
+	return connectorInterface, result.Error
+}
+
+// apply puts the config of the connector, then pauses or resumes it and waits
+// for its state.
+func (c *ConnectorProvider) apply(ctx context.Context, connector models.Connector) error {
+	if len(connector.Config) == 0 {
+		return fmt.Errorf("connector %s has no config", connector.Name)
+	}
+	if name, ok := connector.Config["name"]; ok && name != connector.Name {
+		return fmt.Errorf("config name %s does not match connector %s", name, connector.Name)
+	}
+
+	config, err := c.resolveConfig(connector)
+	if err != nil {
+		return err
+	}
+	err = c.connectClient.PutConfig(ctx, connector.Name, config)
+	if err != nil {
+		return err
+	}
+
+	if connector.Paused {
+		err = c.connectClient.Pause(ctx, connector.Name)
+	} else {
+		err = c.connectClient.Resume(ctx, connector.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.waitForState(ctx, connector)
+}
+
+// waitForState polls the connector until it and all of its tasks reached the
+// expected state, failing early when the connector or a task failed.
+func (c *ConnectorProvider) waitForState(ctx context.Context, connector models.Connector) error {
+	expected := expectedConnectorState(connector)
+	deadline := time.Now().Add(c.connectConfig.WaitTimeout)
+	for {
+		connectorStatus, err := c.connectClient.GetStatus(ctx, connector.Name)
+		if err != nil && !errors.Is(err, kafkaconnect.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			err = checkConnectorState(connectorStatus, expected)
+			if err == nil {
+				return nil
+			}
+			if connectorStatus.Connector.State == connectorStateFailed {
+				return err
+			}
+			for _, task := range connectorStatus.Tasks {
+				if task.State == connectorStateFailed {
+					return err
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("connector %s is not %s after %s: %v", connector.Name, expected, c.connectConfig.WaitTimeout, err)
+		}
+		time.Sleep(c.connectConfig.PollInterval)
+	}
+}
+
+// resolveConfig adds the secrets to the config. Passwords written in the
+// config itself are refused, unless they are placeholders of a Kafka Connect
+// config provider such as ${file:...}.
+func (c *ConnectorProvider) resolveConfig(connector models.Connector) (map[string]string, error) {
+	config := map[string]string{}
+	for key, value := range connector.Config {
+		if strings.HasSuffix(strings.ToLower(key), "password") && !strings.HasPrefix(value, "${") {
+			return nil, fmt.Errorf("config %s of connector %s must be set in secrets", key, connector.Name)
+		}
+		config[key] = value
+	}
+	for key, secret := range connector.Secrets {
+		if _, ok := config[key]; ok {
+			return nil, fmt.Errorf("config %s of connector %s is set both in config and secrets", key, connector.Name)
+		}
+		value, err := resolveSecret(c.secretProviders, secret)
+		if err != nil {
+			return nil, fmt.Errorf("config %s of connector %s: %w", key, connector.Name, err)
+		}
+		config[key] = value
+	}
+	return config, nil
+}
+
+func expectedConnectorState(connector models.Connector) string {
+	if connector.Paused {
+		return connectorStatePaused
+	}
+	return connectorStateRunning
+}
+
+func checkConnectorState(connectorStatus kafkaconnect.ConnectorStatus, expected string) error {
+	if connectorStatus.Connector.State != expected {
+		if connectorStatus.Connector.Trace != "" {
+			return fmt.Errorf("connector is %s: %s", connectorStatus.Connector.State, connectorStatus.Connector.Trace)
+		}
+		return fmt.Errorf("connector is %s", connectorStatus.Connector.State)
+	}
+	if len(connectorStatus.Tasks) == 0 {
+		return errors.New("connector has no tasks")
+	}
+	for _, task := range connectorStatus.Tasks {
+		if task.State != expected {
+			if task.Trace != "" {
+				return fmt.Errorf("task %d is %s: %s", task.ID, task.State, task.Trace)
+			}
+			return fmt.Errorf("task %d is %s", task.ID, task.State)
+		}
+	}
+	return nil
+}
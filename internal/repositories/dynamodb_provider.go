@@ -0,0 +1,826 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+var dynamodbConfigColumns = []string{
+	"billing_mode",
+	"read_capacity",
+	"write_capacity",
+	"ttl_attribute",
+	"point_in_time_recovery",
+	"global_secondary_indexes",
+	"tags",
+	"deletion_policy",
+}
+
+type DynamoDBProvider struct {
+	database       *gorm.DB
+	awsConfig      configs.AWSConfig
+	dynamodbConfig configs.DynamoDBConfig
+}
+
+func InitDynamoDBProvider(db *gorm.DB, awsConfig configs.AWSConfig, dynamodbConfig configs.DynamoDBConfig) Providers {
+	dynamodbProvider := &DynamoDBProvider{}
+	dynamodbProvider.database = db
+	dynamodbProvider.awsConfig = awsConfig
+	dynamodbProvider.dynamodbConfig = dynamodbConfig
+
+	return dynamodbProvider
+}
+
+func (d *DynamoDBProvider) dynamodbClient(account string, region string) (*dynamodb.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(d.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return dynamodb.NewFromConfig(cfg), nil
+}
+
+func (d *DynamoDBProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.DynamoDBTable{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	if component.DeletionPolicy == "" {
+		component.DeletionPolicy = models.DynamoDBDeletionPolicyRetain
+	}
+	return component, nil
+}
+
+func (d *DynamoDBProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.DynamoDBTable
+	if prevData != nil {
+		oldData, ok = prevData.(models.DynamoDBTable)
+		if !ok {
+			err := errors.New("conversion to dynamodb table failed")
+			return nil, err
+		}
+	}
+
+	if prevData != nil && (processed.Account != oldData.Account || processed.Region != oldData.Region) {
+		err := errors.New("account and region of a dynamodb table cannot be changed")
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (d *DynamoDBProvider) InstallComponent(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	err := validateTable(table)
+	if err != nil {
+		return err
+	}
+	attributes, err := attributeDefinitions(table)
+	if err != nil {
+		return err
+	}
+
+	client, err := d.dynamodbClient(table.Account, table.Region)
+	if err != nil {
+		return err
+	}
+
+	input := dynamodb.CreateTableInput{
+		TableName:            &table.Name,
+		AttributeDefinitions: attributes,
+		KeySchema:            keySchema(table.PartitionKey, table.SortKey),
+		BillingMode:          billingMode(table),
+	}
+	if input.BillingMode == types.BillingModeProvisioned {
+		input.ProvisionedThroughput = throughput(table.ReadCapacity, table.WriteCapacity)
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		globalIndex := types.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.PartitionKey, index.SortKey),
+			Projection: projection(index),
+		}
+		if input.BillingMode == types.BillingModeProvisioned {
+			globalIndex.ProvisionedThroughput = throughput(index.ReadCapacity, index.WriteCapacity)
+		}
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, globalIndex)
+	}
+	for key, value := range table.Tags {
+		input.Tags = append(input.Tags, types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	_, err = client.CreateTable(context.TODO(), &input)
+	var inUse *types.ResourceInUseException
+	if errors.As(err, &inUse) {
+		return existingComponent(fmt.Errorf("dynamodb table %s already exists", table.Name))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = d.waitForActive(context.TODO(), client, table.Name)
+	if err != nil {
+		return err
+	}
+	err = d.reconcileTTL(context.TODO(), client, table)
+	if err != nil {
+		return err
+	}
+	return d.reconcileRecovery(context.TODO(), client, table)
+}
+
+// UpdateComponent brings an existing table in line with the spec. DynamoDB
+// accepts a single index creation or deletion per call, so removed indexes are
+// deleted first, then the capacity is updated and the new indexes are created,
+// waiting for the table to settle after each step.
+func (d *DynamoDBProvider) UpdateComponent(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	err := validateTable(table)
+	if err != nil {
+		return err
+	}
+	attributes, err := attributeDefinitions(table)
+	if err != nil {
+		return err
+	}
+
+	client, err := d.dynamodbClient(table.Account, table.Region)
+	if err != nil {
+		return err
+	}
+
+	description, err := d.waitForActive(context.TODO(), client, table.Name)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(description.KeySchema, keySchema(table.PartitionKey, table.SortKey)) {
+		return fmt.Errorf("key schema of dynamodb table %s cannot be changed", table.Name)
+	}
+
+	desired := map[string]models.DynamoDBIndex{}
+	for _, index := range table.GlobalSecondaryIndexes {
+		desired[index.Name] = index
+	}
+	current := map[string]types.GlobalSecondaryIndexDescription{}
+	for _, index := range description.GlobalSecondaryIndexes {
+		current[*index.IndexName] = index
+		if wanted, ok := desired[*index.IndexName]; ok && !sameIndex(index, wanted) {
+			return fmt.Errorf("keys and projection of index %s of dynamodb table %s cannot be changed, add it under another name instead", *index.IndexName, table.Name)
+		}
+	}
+
+	for name := range current {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		err = d.updateTable(context.TODO(), client, &dynamodb.UpdateTableInput{
+			TableName: &table.Name,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: aws.String(name)},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("deleting index %s: %w", name, err)
+		}
+	}
+
+	err = d.reconcileCapacity(context.TODO(), client, table, description, current)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		if _, ok := current[index.Name]; ok {
+			continue
+		}
+		create := &types.CreateGlobalSecondaryIndexAction{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.PartitionKey, index.SortKey),
+			Projection: projection(index),
+		}
+		if billingMode(table) == types.BillingModeProvisioned {
+			create.ProvisionedThroughput = throughput(index.ReadCapacity, index.WriteCapacity)
+		}
+		err = d.updateTable(context.TODO(), client, &dynamodb.UpdateTableInput{
+			TableName:            &table.Name,
+			AttributeDefinitions: attributes,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: create,
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("creating index %s: %w", index.Name, err)
+		}
+	}
+
+	err = d.reconcileTTL(context.TODO(), client, table)
+	if err != nil {
+		return err
+	}
+	err = d.reconcileRecovery(context.TODO(), client, table)
+	if err != nil {
+		return err
+	}
+	return d.reconcileTags(context.TODO(), client, description.TableArn, table.Tags)
+}
+
+// UninstallComponent deletes the table according to its deletion policy.
+// Retained tables are only forgotten and delete-if-empty refuses to delete a
+// table that still holds items.
+func (d *DynamoDBProvider) UninstallComponent(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	switch table.DeletionPolicy {
+	case "", models.DynamoDBDeletionPolicyRetain:
+		return nil
+	case models.DynamoDBDeletionPolicyDeleteIfEmpty, models.DynamoDBDeletionPolicyForce:
+	default:
+		return fmt.Errorf("unknown deletion policy %s", table.DeletionPolicy)
+	}
+
+	client, err := d.dynamodbClient(table.Account, table.Region)
+	if err != nil {
+		return err
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if table.DeletionPolicy == models.DynamoDBDeletionPolicyDeleteIfEmpty {
+		// the item count of DescribeTable is only refreshed every few hours
+		output, err := client.Scan(context.TODO(), &dynamodb.ScanInput{
+			TableName: &table.Name,
+			Limit:     aws.Int32(1),
+			Select:    types.SelectCount,
+		})
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if output.Count > 0 || output.LastEvaluatedKey != nil {
+			return fmt.Errorf("dynamodb table %s is not empty and its deletion policy is %s", table.Name, table.DeletionPolicy)
+		}
+	}
+
+	_, err = client.DeleteTable(context.TODO(), &dynamodb.DeleteTableInput{
+		TableName: &table.Name,
+	})
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	return nil
+}
+
+func (d *DynamoDBProvider) GetStatus(tableInterface interface{}) (responses.ComponentStatus, error) {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: table.Name,
+	}
+
+	client, err := d.dynamodbClient(table.Account, table.Region)
+	if err != nil {
+		return status, err
+	}
+
+	description, err := d.describeTable(context.TODO(), client, table.Name)
+	if err != nil {
+		return status, err
+	}
+
+	detail := responses.DynamoDBStatus{
+		Name:      table.Name,
+		ARN:       aws.ToString(description.TableArn),
+		Status:    string(description.TableStatus),
+		ItemCount: description.ItemCount,
+		SizeBytes: description.TableSizeBytes,
+	}
+	status.Healthy = description.TableStatus == types.TableStatusActive
+	for _, index := range description.GlobalSecondaryIndexes {
+		detail.Indexes = append(detail.Indexes, responses.DynamoDBIndexStatus{
+			Name:        aws.ToString(index.IndexName),
+			Status:      string(index.IndexStatus),
+			Backfilling: aws.ToBool(index.Backfilling),
+		})
+		if index.IndexStatus != types.IndexStatusActive {
+			status.Healthy = false
+		}
+	}
+
+	status.Detail = detail
+	status.Status = string(description.TableStatus)
+	return status, nil
+}
+
+// GetOutputs exposes the arn of the table to the other components of the
+// module as "{table}/arn".
+func (d *DynamoDBProvider) GetOutputs(tableInterface interface{}) (map[string]string, error) {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return nil, err
+	}
+
+	client, err := d.dynamodbClient(table.Account, table.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := d.describeTable(context.TODO(), client, table.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]string{
+		table.Name + "/arn": aws.ToString(description.TableArn),
+	}
+	return outputs, nil
+}
+
+func (d *DynamoDBProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := d.database.Model(&models.DynamoDBTable{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (d *DynamoDBProvider) Add(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	result := d.database.Create(&table)
+	return result.Error
+}
+
+func (d *DynamoDBProvider) Remove(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	result := d.database.Delete(&models.DynamoDBTable{}, "name = ?", table.Name)
+	return result.Error
+}
+
+func (d *DynamoDBProvider) Update(tableInterface interface{}) error {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return err
+	}
+
+	return updateByName(d.database, &table, table.Name, dynamodbConfigColumns)
+}
+
+func (d *DynamoDBProvider) GetDetail(releaseName string) (interface{}, error) {
+	var table models.DynamoDBTable
+	result := d.database.Where("name = ?", releaseName).First(&table)
+	return table, result.Error
+}
+
+func (d *DynamoDBProvider) GetDetailFromComponent(tableInterface interface{}) (interface{}, error) {
+	table, ok := tableInterface.(models.DynamoDBTable)
+	if !ok {
+		err := errors.New("conversion to dynamodb table failed")
+		return nil, err
+	}
+
+	return d.GetDetail(table.Name)
+}
+
+func (d *DynamoDBProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var tables []models.DynamoDBTable
+	result := d.database.Where("module_release_id = ?", ModuleReleaseID).Find(&tables)
+
+	var tableInterface []interface{} = make([]interface{}, len(tables))
+	for i, v := range tables {
+		tableInterface[i] = v
+	}
+
+	return tableInterface, result.Error
+}
+
+func (d *DynamoDBProvider) describeTable(ctx context.Context, client *dynamodb.Client, name string) (*types.TableDescription, error) {
+	output, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: &name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Table, nil
+}
+
+// waitForActive polls the table until it and all of its indexes are ACTIVE,
+// DynamoDB rejects changes to a table while any of them is being updated.
+func (d *DynamoDBProvider) waitForActive(ctx context.Context, client *dynamodb.Client, name string) (*types.TableDescription, error) {
+	deadline := time.Now().Add(d.dynamodbConfig.WaitTimeout)
+	for {
+		description, err := d.describeTable(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+		if description.TableStatus == types.TableStatusDeleting {
+			return nil, fmt.Errorf("dynamodb table %s is being deleted", name)
+		}
+		pending := ""
+		if description.TableStatus != types.TableStatusActive {
+			pending = "table is " + string(description.TableStatus)
+		}
+		for _, index := range description.GlobalSecondaryIndexes {
+			if index.IndexStatus != types.IndexStatusActive {
+				pending = fmt.Sprintf("index %s is %s", aws.ToString(index.IndexName), index.IndexStatus)
+			}
+		}
+		if pending == "" {
+			return description, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("dynamodb table %s is not active after %s: %s", name, d.dynamodbConfig.WaitTimeout, pending)
+		}
+		time.Sleep(d.dynamodbConfig.PollInterval)
+	}
+}
+
+func (d *DynamoDBProvider) updateTable(ctx context.Context, client *dynamodb.Client, input *dynamodb.UpdateTableInput) error {
+	_, err := client.UpdateTable(ctx, input)
+	if err != nil {
+		return err
+	}
+	_, err = d.waitForActive(ctx, client, *input.TableName)
+	return err
+}
+
+// reconcileCapacity switches the billing mode and updates the provisioned
+// throughput of the table and of the indexes it keeps.
+func (d *DynamoDBProvider) reconcileCapacity(ctx context.Context, client *dynamodb.Client, table models.DynamoDBTable, description *types.TableDescription, current map[string]types.GlobalSecondaryIndexDescription) error {
+	currentMode := types.BillingModeProvisioned
+	if description.BillingModeSummary != nil && description.BillingModeSummary.BillingMode != "" {
+		currentMode = description.BillingModeSummary.BillingMode
+	}
+	mode := billingMode(table)
+
+	input := dynamodb.UpdateTableInput{
+		TableName: &table.Name,
+	}
+	changed := false
+	if currentMode != mode {
+		input.BillingMode = mode
+		changed = true
+	}
+	if mode == types.BillingModeProvisioned {
+		if currentMode != mode || !sameThroughput(description.ProvisionedThroughput, table.ReadCapacity, table.WriteCapacity) {
+			input.ProvisionedThroughput = throughput(table.ReadCapacity, table.WriteCapacity)
+			changed = true
+		}
+		// switching to provisioned requires the throughput of every index
+		for _, index := range table.GlobalSecondaryIndexes {
+			currentIndex, ok := current[index.Name]
+			if !ok {
+				continue
+			}
+			if currentMode == mode && sameThroughput(currentIndex.ProvisionedThroughput, index.ReadCapacity, index.WriteCapacity) {
+				continue
+			}
+			input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+				Update: &types.UpdateGlobalSecondaryIndexAction{
+					IndexName:             aws.String(index.Name),
+					ProvisionedThroughput: throughput(index.ReadCapacity, index.WriteCapacity),
+				},
+			})
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	err := d.updateTable(ctx, client, &input)
+	if err != nil {
+		return fmt.Errorf("updating capacity: %w", err)
+	}
+	return nil
+}
+
+// reconcileTTL enables or disables the time to live attribute. DynamoDB only
+// allows one change per hour, so moving it to another attribute is refused
+// and has to be done by removing it first.
+func (d *DynamoDBProvider) reconcileTTL(ctx context.Context, client *dynamodb.Client, table models.DynamoDBTable) error {
+	output, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: &table.Name,
+	})
+	if err != nil {
+		return err
+	}
+	currentAttribute := ""
+	if output.TimeToLiveDescription != nil {
+		switch output.TimeToLiveDescription.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			currentAttribute = aws.ToString(output.TimeToLiveDescription.AttributeName)
+		}
+	}
+	if currentAttribute == table.TTLAttribute {
+		return nil
+	}
+	if currentAttribute != "" && table.TTLAttribute != "" {
+		return fmt.Errorf("time to live of dynamodb table %s is set on %s, remove it before setting it on %s", table.Name, currentAttribute, table.TTLAttribute)
+	}
+
+	specification := &types.TimeToLiveSpecification{
+		AttributeName: aws.String(table.TTLAttribute),
+		Enabled:       aws.Bool(true),
+	}
+	if table.TTLAttribute == "" {
+		specification.AttributeName = aws.String(currentAttribute)
+		specification.Enabled = aws.Bool(false)
+	}
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName:               &table.Name,
+		TimeToLiveSpecification: specification,
+	})
+	return err
+}
+
+func (d *DynamoDBProvider) reconcileRecovery(ctx context.Context, client *dynamodb.Client, table models.DynamoDBTable) error {
+	output, err := client.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: &table.Name,
+	})
+	if err != nil {
+		return err
+	}
+	enabled := false
+	if output.ContinuousBackupsDescription != nil && output.ContinuousBackupsDescription.PointInTimeRecoveryDescription != nil {
+		enabled = output.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus == types.PointInTimeRecoveryStatusEnabled
+	}
+	if enabled == table.PointInTimeRecovery {
+		return nil
+	}
+
+	_, err = client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName: &table.Name,
+		PointInTimeRecoverySpecification: &types.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(table.PointInTimeRecovery),
+		},
+	})
+	return err
+}
+
+func (d *DynamoDBProvider) reconcileTags(ctx context.Context, client *dynamodb.Client, arn *string, tags map[string]string) error {
+	current := map[string]string{}
+	var nextToken *string
+	for {
+		output, err := client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{
+			ResourceArn: arn,
+			NextToken:   nextToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, tag := range output.Tags {
+			current[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	var removed []string
+	for key := range current {
+		if _, ok := tags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) > 0 {
+		_, err := client.UntagResource(ctx, &dynamodb.UntagResourceInput{
+			ResourceArn: arn,
+			TagKeys:     removed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var changed []types.Tag
+	for key, value := range tags {
+		if currentValue, ok := current[key]; ok && currentValue == value {
+			continue
+		}
+		changed = append(changed, types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	_, err := client.TagResource(ctx, &dynamodb.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        changed,
+	})
+	return err
+}
+
+func validateTable(table models.DynamoDBTable) error {
+	if table.PartitionKey.Name == "" {
+		return fmt.Errorf("dynamodb table %s has no partition key", table.Name)
+	}
+	switch billingMode(table) {
+	case types.BillingModePayPerRequest:
+	case types.BillingModeProvisioned:
+		if table.ReadCapacity <= 0 || table.WriteCapacity <= 0 {
+			return fmt.Errorf("provisioned dynamodb table %s needs read and write capacity", table.Name)
+		}
+	default:
+		return fmt.Errorf("unknown billing mode %s", table.BillingMode)
+	}
+
+	names := map[string]bool{}
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.Name == "" || index.PartitionKey.Name == "" {
+			return fmt.Errorf("indexes of dynamodb table %s need a name and a partition key", table.Name)
+		}
+		if names[index.Name] {
+			return fmt.Errorf("index %s of dynamodb table %s is declared twice", index.Name, table.Name)
+		}
+		names[index.Name] = true
+		switch projection(index).ProjectionType {
+		case types.ProjectionTypeAll, types.ProjectionTypeKeysOnly, types.ProjectionTypeInclude:
+		default:
+			return fmt.Errorf("unknown projection type %s of index %s", index.ProjectionType, index.Name)
+		}
+		if billingMode(table) == types.BillingModeProvisioned && (index.ReadCapacity <= 0 || index.WriteCapacity <= 0) {
+			return fmt.Errorf("index %s of provisioned dynamodb table %s needs read and write capacity", index.Name, table.Name)
+		}
+	}
+
+	switch table.DeletionPolicy {
+	case "", models.DynamoDBDeletionPolicyRetain, models.DynamoDBDeletionPolicyDeleteIfEmpty, models.DynamoDBDeletionPolicyForce:
+	default:
+		return fmt.Errorf("unknown deletion policy %s", table.DeletionPolicy)
+	}
+	return nil
+}
+
+// attributeDefinitions lists the key attributes of the table and its indexes,
+// an attribute used by several keys must have the same type in all of them.
+func attributeDefinitions(table models.DynamoDBTable) ([]types.AttributeDefinition, error) {
+	keys := []models.DynamoDBKey{table.PartitionKey, table.SortKey}
+	for _, index := range table.GlobalSecondaryIndexes {
+		keys = append(keys, index.PartitionKey, index.SortKey)
+	}
+
+	seen := map[string]types.ScalarAttributeType{}
+	var definitions []types.AttributeDefinition
+	for _, key := range keys {
+		if key.Name == "" {
+			continue
+		}
+		attributeType := attributeType(key)
+		switch attributeType {
+		case types.ScalarAttributeTypeS, types.ScalarAttributeTypeN, types.ScalarAttributeTypeB:
+		default:
+			return nil, fmt.Errorf("unknown type %s of key %s", key.Type, key.Name)
+		}
+		if seenType, ok := seen[key.Name]; ok {
+			if seenType != attributeType {
+				return nil, fmt.Errorf("key %s is used with types %s and %s", key.Name, seenType, attributeType)
+			}
+			continue
+		}
+		seen[key.Name] = attributeType
+		definitions = append(definitions, types.AttributeDefinition{
+			AttributeName: aws.String(key.Name),
+			AttributeType: attributeType,
+		})
+	}
+	return definitions, nil
+}
+
+func keySchema(partitionKey models.DynamoDBKey, sortKey models.DynamoDBKey) []types.KeySchemaElement {
+	schema := []types.KeySchemaElement{{
+		AttributeName: aws.String(partitionKey.Name),
+		KeyType:       types.KeyTypeHash,
+	}}
+	if sortKey.Name != "" {
+		schema = append(schema, types.KeySchemaElement{
+			AttributeName: aws.String(sortKey.Name),
+			KeyType:       types.KeyTypeRange,
+		})
+	}
+	return schema
+}
+
+func projection(index models.DynamoDBIndex) *types.Projection {
+	projection := &types.Projection{
+		ProjectionType: types.ProjectionType(strings.ToUpper(index.ProjectionType)),
+	}
+	if projection.ProjectionType == "" {
+		projection.ProjectionType = types.ProjectionTypeAll
+	}
+	if projection.ProjectionType == types.ProjectionTypeInclude {
+		projection.NonKeyAttributes = index.NonKeyAttributes
+	}
+	return projection
+}
+
+func sameIndex(current types.GlobalSecondaryIndexDescription, index models.DynamoDBIndex) bool {
+	if !reflect.DeepEqual(current.KeySchema, keySchema(index.PartitionKey, index.SortKey)) {
+		return false
+	}
+	wanted := projection(index)
+	if current.Projection == nil || current.Projection.ProjectionType != wanted.ProjectionType {
+		return false
+	}
+	return stringSet(current.Projection.NonKeyAttributes) == stringSet(wanted.NonKeyAttributes)
+}
+
+func sameThroughput(current *types.ProvisionedThroughputDescription, read int64, write int64) bool {
+	if current == nil {
+		return false
+	}
+	return aws.ToInt64(current.ReadCapacityUnits) == read && aws.ToInt64(current.WriteCapacityUnits) == write
+}
+
+func billingMode(table models.DynamoDBTable) types.BillingMode {
+	if table.BillingMode == "" {
+		return types.BillingModePayPerRequest
+	}
+	return types.BillingMode(strings.ToUpper(table.BillingMode))
+}
+
+func attributeType(key models.DynamoDBKey) types.ScalarAttributeType {
+	if key.Type == "" {
+		return types.ScalarAttributeTypeS
+	}
+	return types.ScalarAttributeType(strings.ToUpper(key.Type))
+}
+
+func throughput(read int64, write int64) *types.ProvisionedThroughput {
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(read),
+		WriteCapacityUnits: aws.Int64(write),
+	}
+}
+
+func stringSet(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
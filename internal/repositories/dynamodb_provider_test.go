@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+func TestAttributeDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   models.DynamoDBTable
+		want    []types.AttributeDefinition
+		wantErr bool
+	}{
+		{
+			name:  "partition key defaults to string",
+			table: models.DynamoDBTable{PartitionKey: models.DynamoDBKey{Name: "id"}},
+			want:  []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
+		},
+		{
+			name: "keys shared with an index are defined once",
+			table: models.DynamoDBTable{
+				PartitionKey: models.DynamoDBKey{Name: "id"},
+				SortKey:      models.DynamoDBKey{Name: "created_at", Type: "n"},
+				GlobalSecondaryIndexes: models.DynamoDBIndexes{
+					{Name: "by-customer", PartitionKey: models.DynamoDBKey{Name: "customer"}, SortKey: models.DynamoDBKey{Name: "created_at", Type: "N"}},
+				},
+			},
+			want: []types.AttributeDefinition{
+				{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+				{AttributeName: aws.String("created_at"), AttributeType: types.ScalarAttributeTypeN},
+				{AttributeName: aws.String("customer"), AttributeType: types.ScalarAttributeTypeS},
+			},
+		},
+		{
+			name: "key used with two types",
+			table: models.DynamoDBTable{
+				PartitionKey: models.DynamoDBKey{Name: "id"},
+				GlobalSecondaryIndexes: models.DynamoDBIndexes{
+					{Name: "by-id", PartitionKey: models.DynamoDBKey{Name: "id", Type: "N"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			table:   models.DynamoDBTable{PartitionKey: models.DynamoDBKey{Name: "id", Type: "BOOL"}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := attributeDefinitions(test.table)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSameIndex(t *testing.T) {
+	index := models.DynamoDBIndex{
+		Name:             "by-customer",
+		PartitionKey:     models.DynamoDBKey{Name: "customer"},
+		SortKey:          models.DynamoDBKey{Name: "created_at", Type: "N"},
+		ProjectionType:   "include",
+		NonKeyAttributes: []string{"total", "status"},
+	}
+	current := func() types.GlobalSecondaryIndexDescription {
+		return types.GlobalSecondaryIndexDescription{
+			IndexName: aws.String("by-customer"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("customer"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("created_at"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{
+				ProjectionType:   types.ProjectionTypeInclude,
+				NonKeyAttributes: []string{"status", "total"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*types.GlobalSecondaryIndexDescription)
+		want   bool
+	}{
+		{name: "same, attributes in another order", change: func(*types.GlobalSecondaryIndexDescription) {}, want: true},
+		{name: "other sort key", change: func(d *types.GlobalSecondaryIndexDescription) {
+			d.KeySchema[1].AttributeName = aws.String("updated_at")
+		}},
+		{name: "no sort key", change: func(d *types.GlobalSecondaryIndexDescription) { d.KeySchema = d.KeySchema[:1] }},
+		{name: "other projection", change: func(d *types.GlobalSecondaryIndexDescription) { d.Projection.ProjectionType = types.ProjectionTypeAll }},
+		{name: "other attributes", change: func(d *types.GlobalSecondaryIndexDescription) { d.Projection.NonKeyAttributes = []string{"total"} }},
+		{name: "no projection", change: func(d *types.GlobalSecondaryIndexDescription) { d.Projection = nil }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			description := current()
+			test.change(&description)
+			if got := sameIndex(description, index); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("projection defaults to all", func(t *testing.T) {
+		description := current()
+		description.Projection = &types.Projection{ProjectionType: types.ProjectionTypeAll}
+		if !sameIndex(description, models.DynamoDBIndex{PartitionKey: index.PartitionKey, SortKey: index.SortKey}) {
+			t.Error("an index without projection type differs from an ALL projection")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"go.mozilla.org/sops/v3"
+	sopsaes "go.mozilla.org/sops/v3/aes"
+	sopsage "go.mozilla.org/sops/v3/age"
+	sopsjson "go.mozilla.org/sops/v3/stores/json"
+	sopsyaml "go.mozilla.org/sops/v3/stores/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// FileSecretProvider reads secrets from a local YAML or JSON file, meant for
+// running the controller without a secret store. The file is either plain or
+// encrypted by SOPS with age recipients.
+type FileSecretProvider struct {
+	fileConfig configs.SecretFileConfig
+}
+
+func InitFileSecretProvider(fileConfig configs.SecretFileConfig) SecretProviders {
+	fileSecretProvider := &FileSecretProvider{}
+	fileSecretProvider.fileConfig = fileConfig
+	return fileSecretProvider
+}
+
+// GetSecret reads the value at a dotted path of the file, e.g.
+// database.password. The file is read again on every call so edits are
+// picked up without a restart.
+func (f FileSecretProvider) GetSecret(key string) (string, error) {
+	content, err := ioutil.ReadFile(f.fileConfig.Path)
+	if err != nil {
+		return "", err
+	}
+	document := map[string]interface{}{}
+	err = yaml.Unmarshal(content, &document)
+	if err != nil {
+		return "", fmt.Errorf("parsing secret file: %w", err)
+	}
+	if _, ok := document["sops"]; ok {
+		document, err = f.decrypt(content)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	path := strings.Split(key, ".")
+	var value interface{} = document
+	for _, part := range path {
+		branch, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("key %s does not exist", key)
+		}
+		value, ok = branch[part]
+		if !ok {
+			return "", fmt.Errorf("key %s does not exist", key)
+		}
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return "", fmt.Errorf("key %s is not a single value", key)
+		}
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	}
+	return text, nil
+}
+
+// decrypt opens a SOPS file and checks its MAC, which covers every value of
+// the file, so values cannot be swapped, removed or edited unnoticed.
+func (f FileSecretProvider) decrypt(content []byte) (map[string]interface{}, error) {
+	var store sops.Store = &sopsyaml.Store{}
+	if strings.EqualFold(filepath.Ext(f.fileConfig.Path), ".json") {
+		store = &sopsjson.Store{}
+	}
+	tree, err := store.LoadEncryptedFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing secret file: %w", err)
+	}
+
+	dataKey, err := f.dataKey(tree.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	cipher := sopsaes.NewCipher()
+	mac, err := tree.Decrypt(dataKey, cipher)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secret file: %w", err)
+	}
+	fileMac, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey, tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secret file mac: %w", err)
+	}
+	if fileMac != mac {
+		return nil, errors.New("secret file does not match its mac")
+	}
+
+	plain, err := store.EmitPlainFile(tree.Branches)
+	if err != nil {
+		return nil, err
+	}
+	document := map[string]interface{}{}
+	err = yaml.Unmarshal(plain, &document)
+	return document, err
+}
+
+// dataKey decrypts the SOPS data key with the first age identity that matches
+// one of the recipients of the file.
+func (f FileSecretProvider) dataKey(metadata sops.Metadata) ([]byte, error) {
+	if f.fileConfig.AgeKeyFile == "" {
+		return nil, errors.New("no age key file configured to decrypt the secret file")
+	}
+	keyFile, err := os.Open(f.fileConfig.AgeKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	defer keyFile.Close()
+	identities, err := age.ParseIdentities(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("parsing age key file: %w", err)
+	}
+
+	recipients := 0
+	for _, group := range metadata.KeyGroups {
+		for _, key := range group {
+			recipient, ok := key.(*sopsage.MasterKey)
+			if !ok {
+				continue
+			}
+			recipients++
+			reader, err := age.Decrypt(armor.NewReader(strings.NewReader(recipient.EncryptedKey)), identities...)
+			if err != nil {
+				continue
+			}
+			buffer := bytes.Buffer{}
+			_, err = buffer.ReadFrom(reader)
+			if err != nil {
+				return nil, err
+			}
+			return buffer.Bytes(), nil
+		}
+	}
+	if recipients == 0 {
+		return nil, errors.New("secret file has no age recipients")
+	}
+	return nil, errors.New("none of the age identities can decrypt the secret file")
+}
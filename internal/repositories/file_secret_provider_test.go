@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"go.mozilla.org/sops/v3"
+	sopsaes "go.mozilla.org/sops/v3/aes"
+	sopsage "go.mozilla.org/sops/v3/age"
+	sopsyaml "go.mozilla.org/sops/v3/stores/yaml"
+)
+
+// encryptSopsFile encrypts the password values of a YAML document the way
+// SOPS does, with the data key sealed for the age recipient.
+func encryptSopsFile(t *testing.T, plain []byte, dataKey []byte, recipient string) []byte {
+	t.Helper()
+	store := &sopsyaml.Store{}
+	branches, err := store.LoadPlainFile(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &sopsage.MasterKey{Recipient: recipient}
+	err = key.Encrypt(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := sops.Tree{
+		Branches: branches,
+		Metadata: sops.Metadata{
+			LastModified:   time.Now().UTC(),
+			EncryptedRegex: "^password$",
+			Version:        "3.7.3",
+			KeyGroups:      []sops.KeyGroup{{key}},
+		},
+	}
+
+	cipher := sopsaes.NewCipher()
+	mac, err := tree.Encrypt(dataKey, cipher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree.Metadata.MessageAuthenticationCode, err = cipher.Encrypt(mac, dataKey, tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := store.EmitEncryptedFile(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encrypted
+}
+
+func TestFileSecretProviderGetSecret(t *testing.T) {
+	directory := t.TempDir()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ageKeyFile := filepath.Join(directory, "keys.txt")
+	err = ioutil.WriteFile(ageKeyFile, []byte(identity.String()+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataKey := make([]byte, 32)
+	_, err = rand.Read(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte("database:\n  username: warehouse\n  port: 5432\n  password: s3cret\n  hosts:\n  - primary\n  - replica\n")
+	content := encryptSopsFile(t, plain, dataKey, identity.Recipient().String())
+	if bytes.Contains(content, []byte("s3cret")) {
+		t.Fatal("password was not encrypted")
+	}
+	path := filepath.Join(directory, "secrets.yaml")
+	err = ioutil.WriteFile(path, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := InitFileSecretProvider(configs.SecretFileConfig{Path: path, AgeKeyFile: ageKeyFile})
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain value", key: "database.username", want: "warehouse"},
+		{name: "number", key: "database.port", want: "5432"},
+		{name: "encrypted value", key: "database.password", want: "s3cret"},
+		{name: "missing key", key: "database.schema", wantErr: true},
+		{name: "path through a value", key: "database.username.first", wantErr: true},
+		{name: "object", key: "database", wantErr: true},
+		{name: "list", key: "database.hosts", wantErr: true},
+		{name: "sops metadata", key: "sops.age", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := provider.GetSecret(test.key)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("edited value", func(t *testing.T) {
+		edited := filepath.Join(directory, "edited.yaml")
+		err := ioutil.WriteFile(edited, bytes.Replace(content, []byte("warehouse"), []byte("intruder"), 1), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		provider := InitFileSecretProvider(configs.SecretFileConfig{Path: edited, AgeKeyFile: ageKeyFile})
+		_, err = provider.GetSecret("database.password")
+		if err == nil {
+			t.Error("got no error reading a file that does not match its mac")
+		}
+	})
+
+	t.Run("no age key file", func(t *testing.T) {
+		provider := InitFileSecretProvider(configs.SecretFileConfig{Path: path})
+		_, err := provider.GetSecret("database.password")
+		if err == nil {
+			t.Error("got no error decrypting without an age key file")
+		}
+	})
+}
@@ -0,0 +1,473 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/firehose/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+var firehoseConfigColumns = []string{
+	"destination",
+	"role_arn",
+	"bucket_arn",
+	"prefix",
+	"error_output_prefix",
+	"buffering_size_mb",
+	"buffering_interval_seconds",
+	"compression_format",
+	"redshift_cluster_jdbc_url",
+	"redshift_username",
+	"redshift_password_secret_provider",
+	"redshift_password_secret_key",
+	"redshift_table",
+	"redshift_columns",
+	"redshift_copy_options",
+}
+
+type FirehoseProvider struct {
+	database        *gorm.DB
+	awsConfig       configs.AWSConfig
+	kinesisConfig   configs.KinesisConfig
+	secretProviders map[string]SecretProviders
+}
+
+func InitFirehoseProvider(db *gorm.DB, awsConfig configs.AWSConfig, kinesisConfig configs.KinesisConfig, secretProviders map[string]SecretProviders) Providers {
+	firehoseProvider := &FirehoseProvider{}
+	firehoseProvider.database = db
+	firehoseProvider.awsConfig = awsConfig
+	firehoseProvider.kinesisConfig = kinesisConfig
+	firehoseProvider.secretProviders = secretProviders
+
+	return firehoseProvider
+}
+
+func (f *FirehoseProvider) firehoseClient(account string, region string) (*firehose.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(f.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return firehose.NewFromConfig(cfg), nil
+}
+
+func (f *FirehoseProvider) kinesisClient(account string, region string) (*kinesis.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(f.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return kinesis.NewFromConfig(cfg), nil
+}
+
+func (f *FirehoseProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Firehose{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (f *FirehoseProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Firehose
+	if prevData != nil {
+		oldData, ok = prevData.(models.Firehose)
+		if !ok {
+			err := errors.New("conversion to firehose failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (f *FirehoseProvider) InstallComponent(firehoseInterface interface{}) error {
+	firehoseData, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	client, err := f.firehoseClient(firehoseData.Account, firehoseData.Region)
+	if err != nil {
+		return err
+	}
+
+	input := firehose.CreateDeliveryStreamInput{
+		DeliveryStreamName: &firehoseData.Name,
+		DeliveryStreamType: types.DeliveryStreamTypeDirectPut,
+	}
+
+	if firehoseData.SourceStream != "" {
+		streamARN, err := f.getStreamARN(firehoseData)
+		if err != nil {
+			return err
+		}
+		input.DeliveryStreamType = types.DeliveryStreamTypeKinesisStreamAsSource
+		input.KinesisStreamSourceConfiguration = &types.KinesisStreamSourceConfiguration{
+			KinesisStreamARN: streamARN,
+			RoleARN:          &firehoseData.SourceRoleARN,
+		}
+	}
+
+	switch firehoseData.Destination {
+	case "", models.FirehoseDestinationS3:
+		input.ExtendedS3DestinationConfiguration = &types.ExtendedS3DestinationConfiguration{
+			BucketARN:         &firehoseData.BucketARN,
+			RoleARN:           &firehoseData.RoleARN,
+			BufferingHints:    bufferingHints(firehoseData),
+			CompressionFormat: types.CompressionFormat(firehoseData.CompressionFormat),
+			Prefix:            optionalString(firehoseData.Prefix),
+			ErrorOutputPrefix: optionalString(firehoseData.ErrorOutputPrefix),
+		}
+	case models.FirehoseDestinationRedshift:
+		password, err := f.redshiftPassword(firehoseData)
+		if err != nil {
+			return err
+		}
+		input.RedshiftDestinationConfiguration = &types.RedshiftDestinationConfiguration{
+			ClusterJDBCURL: &firehoseData.Redshift.ClusterJDBCURL,
+			CopyCommand:    copyCommand(firehoseData),
+			Username:       &firehoseData.Redshift.Username,
+			Password:       password,
+			RoleARN:        &firehoseData.RoleARN,
+			S3Configuration: &types.S3DestinationConfiguration{
+				BucketARN:         &firehoseData.BucketARN,
+				RoleARN:           &firehoseData.RoleARN,
+				BufferingHints:    bufferingHints(firehoseData),
+				CompressionFormat: types.CompressionFormat(firehoseData.CompressionFormat),
+				Prefix:            optionalString(firehoseData.Prefix),
+				ErrorOutputPrefix: optionalString(firehoseData.ErrorOutputPrefix),
+			},
+		}
+	default:
+		return fmt.Errorf("unknown firehose destination %s", firehoseData.Destination)
+	}
+
+	_, err = client.CreateDeliveryStream(context.TODO(), &input)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.waitForActive(context.TODO(), client, firehoseData.Name)
+	return err
+}
+
+func (f *FirehoseProvider) UpdateComponent(firehoseInterface interface{}) error {
+	firehoseData, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	client, err := f.firehoseClient(firehoseData.Account, firehoseData.Region)
+	if err != nil {
+		return err
+	}
+
+	description, err := f.waitForActive(context.TODO(), client, firehoseData.Name)
+	if err != nil {
+		return err
+	}
+	if len(description.Destinations) == 0 {
+		return fmt.Errorf("firehose delivery stream %s has no destination", firehoseData.Name)
+	}
+
+	currentSource := ""
+	if description.Source != nil && description.Source.KinesisStreamSourceDescription != nil {
+		currentSource = *description.Source.KinesisStreamSourceDescription.KinesisStreamARN
+	}
+	desiredSource := ""
+	if firehoseData.SourceStream != "" {
+		streamARN, err := f.getStreamARN(firehoseData)
+		if err != nil {
+			return err
+		}
+		desiredSource = *streamARN
+	}
+	if currentSource != desiredSource {
+		return fmt.Errorf("source of firehose delivery stream %s cannot be changed", firehoseData.Name)
+	}
+
+	input := firehose.UpdateDestinationInput{
+		DeliveryStreamName:             &firehoseData.Name,
+		CurrentDeliveryStreamVersionId: description.VersionId,
+		DestinationId:                  description.Destinations[0].DestinationId,
+	}
+
+	switch firehoseData.Destination {
+	case "", models.FirehoseDestinationS3:
+		input.ExtendedS3DestinationUpdate = &types.ExtendedS3DestinationUpdate{
+			BucketARN:         &firehoseData.BucketARN,
+			RoleARN:           &firehoseData.RoleARN,
+			BufferingHints:    bufferingHints(firehoseData),
+			CompressionFormat: types.CompressionFormat(firehoseData.CompressionFormat),
+			Prefix:            optionalString(firehoseData.Prefix),
+			ErrorOutputPrefix: optionalString(firehoseData.ErrorOutputPrefix),
+		}
+	case models.FirehoseDestinationRedshift:
+		password, err := f.redshiftPassword(firehoseData)
+		if err != nil {
+			return err
+		}
+		input.RedshiftDestinationUpdate = &types.RedshiftDestinationUpdate{
+			ClusterJDBCURL: &firehoseData.Redshift.ClusterJDBCURL,
+			CopyCommand:    copyCommand(firehoseData),
+			Username:       &firehoseData.Redshift.Username,
+			Password:       password,
+			RoleARN:        &firehoseData.RoleARN,
+			S3Update: &types.S3DestinationUpdate{
+				BucketARN:         &firehoseData.BucketARN,
+				RoleARN:           &firehoseData.RoleARN,
+				BufferingHints:    bufferingHints(firehoseData),
+				CompressionFormat: types.CompressionFormat(firehoseData.CompressionFormat),
+				Prefix:            optionalString(firehoseData.Prefix),
+				ErrorOutputPrefix: optionalString(firehoseData.ErrorOutputPrefix),
+			},
+		}
+	default:
+		return fmt.Errorf("unknown firehose destination %s", firehoseData.Destination)
+	}
+
+	_, err = client.UpdateDestination(context.TODO(), &input)
+	return err
+}
+
+func (f *FirehoseProvider) UninstallComponent(firehoseInterface interface{}) error {
+	firehoseData, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	client, err := f.firehoseClient(firehoseData.Account, firehoseData.Region)
+	if err != nil {
+		return err
+	}
+
+	input := firehose.DeleteDeliveryStreamInput{
+		DeliveryStreamName: &firehoseData.Name,
+	}
+	_, err = client.DeleteDeliveryStream(context.TODO(), &input)
+	return err
+}
+
+func (f *FirehoseProvider) GetStatus(firehoseInterface interface{}) (responses.ComponentStatus, error) {
+	firehoseData, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: firehoseData.Name,
+	}
+
+	client, err := f.firehoseClient(firehoseData.Account, firehoseData.Region)
+	if err != nil {
+		return status, err
+	}
+
+	description, err := f.describeDeliveryStream(context.TODO(), client, firehoseData.Name)
+	if err != nil {
+		return status, err
+	}
+
+	status.Healthy = description.DeliveryStreamStatus == types.DeliveryStreamStatusActive
+	status.Status = string(description.DeliveryStreamStatus)
+	if description.FailureDescription != nil && description.FailureDescription.Details != nil {
+		status.Message = *description.FailureDescription.Details
+	}
+	return status, nil
+}
+
+func (f *FirehoseProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := f.database.Model(&models.Firehose{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (f *FirehoseProvider) Add(firehoseInterface interface{}) error {
+	firehose, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	result := f.database.Create(&firehose)
+	return result.Error
+}
+
+func (f *FirehoseProvider) Remove(firehoseInterface interface{}) error {
+	firehose, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	result := f.database.Delete(&models.Firehose{}, "name = ?", firehose.Name)
+	return result.Error
+}
+
+func (f *FirehoseProvider) Update(firehoseInterface interface{}) error {
+	firehose, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return err
+	}
+
+	return updateByName(f.database, &firehose, firehose.Name, firehoseConfigColumns)
+}
+
+func (f *FirehoseProvider) GetDetail(releaseName string) (interface{}, error) {
+	var firehose models.Firehose
+	result := f.database.Where("name = ?", releaseName).First(&firehose)
+	return firehose, result.Error
+}
+
+func (f *FirehoseProvider) GetDetailFromComponent(firehoseInterface interface{}) (interface{}, error) {
+	firehose, ok := firehoseInterface.(models.Firehose)
+	if !ok {
+		err := errors.New("conversion to firehose failed")
+		return nil, err
+	}
+
+	return f.GetDetail(firehose.Name)
+}
+
+func (f *FirehoseProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var firehose []models.Firehose
+	result := f.database.Where("module_release_id = ?", ModuleReleaseID).Find(&firehose)
+
+	var firehoseInterface []interface{} = make([]interface{}, len(firehose))
+	for i, v := range firehose {
+		firehoseInterface[i] = v
+	}
+
+	return firehoseInterface, result.Error
+}
+
+// getStreamARN looks up the source kinesis stream, which lives in the same
+// account and region as the delivery stream.
+func (f *FirehoseProvider) getStreamARN(firehoseData models.Firehose) (*string, error) {
+	client, err := f.kinesisClient(firehoseData.Account, firehoseData.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := kinesis.DescribeStreamSummaryInput{
+		StreamName: &firehoseData.SourceStream,
+	}
+	output, err := client.DescribeStreamSummary(context.TODO(), &input)
+	if err != nil {
+		return nil, err
+	}
+	return output.StreamDescriptionSummary.StreamARN, nil
+}
+
+func (f *FirehoseProvider) describeDeliveryStream(ctx context.Context, client *firehose.Client, name string) (*types.DeliveryStreamDescription, error) {
+	input := firehose.DescribeDeliveryStreamInput{
+		DeliveryStreamName: &name,
+	}
+	output, err := client.DescribeDeliveryStream(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	return output.DeliveryStreamDescription, nil
+}
+
+// waitForActive polls the delivery stream until it is ACTIVE, destinations
+// cannot be updated while it is still being created.
+func (f *FirehoseProvider) waitForActive(ctx context.Context, client *firehose.Client, name string) (*types.DeliveryStreamDescription, error) {
+	deadline := time.Now().Add(f.kinesisConfig.WaitTimeout)
+	for {
+		description, err := f.describeDeliveryStream(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+		switch description.DeliveryStreamStatus {
+		case types.DeliveryStreamStatusActive:
+			return description, nil
+		case types.DeliveryStreamStatusCreatingFailed:
+			message := ""
+			if description.FailureDescription != nil && description.FailureDescription.Details != nil {
+				message = *description.FailureDescription.Details
+			}
+			return nil, fmt.Errorf("firehose delivery stream %s failed to create: %s", name, message)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("firehose delivery stream %s is still %s after %s", name, description.DeliveryStreamStatus, f.kinesisConfig.WaitTimeout)
+		}
+		time.Sleep(f.kinesisConfig.PollInterval)
+	}
+}
+
+// redshiftPassword reads the password of the Redshift user from the secret
+// providers.
+func (f *FirehoseProvider) redshiftPassword(firehoseData models.Firehose) (*string, error) {
+	if firehoseData.Redshift.PasswordSecret.Key == "" {
+		return nil, fmt.Errorf("redshift destination of firehose %s needs a password_secret", firehoseData.Name)
+	}
+	password, err := resolveSecret(f.secretProviders, firehoseData.Redshift.PasswordSecret)
+	if err != nil {
+		return nil, fmt.Errorf("password of firehose %s: %w", firehoseData.Name, err)
+	}
+	return &password, nil
+}
+
+func bufferingHints(firehoseData models.Firehose) *types.BufferingHints {
+	if firehoseData.BufferingSizeMB == 0 && firehoseData.BufferingIntervalSeconds == 0 {
+		return nil
+	}
+	hints := &types.BufferingHints{}
+	if firehoseData.BufferingSizeMB != 0 {
+		hints.SizeInMBs = &firehoseData.BufferingSizeMB
+	}
+	if firehoseData.BufferingIntervalSeconds != 0 {
+		hints.IntervalInSeconds = &firehoseData.BufferingIntervalSeconds
+	}
+	return hints
+}
+
+func copyCommand(firehoseData models.Firehose) *types.CopyCommand {
+	return &types.CopyCommand{
+		DataTableName:    &firehoseData.Redshift.Table,
+		DataTableColumns: optionalString(firehoseData.Redshift.Columns),
+		CopyOptions:      optionalString(firehoseData.Redshift.CopyOptions),
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
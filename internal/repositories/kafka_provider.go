@@ -0,0 +1,377 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+var kafkaConfigColumns = []string{
+	"configs",
+	"import",
+}
+
+type KafkaProvider struct {
+	database    *gorm.DB
+	kafkaClient *kafka.Client
+	kafkaConfig configs.KafkaConfig
+}
+
+func InitKafkaProvider(db *gorm.DB, kafkaClient *kafka.Client, kafkaConfig configs.KafkaConfig) Providers {
+	kafkaProvider := &KafkaProvider{}
+	kafkaProvider.database = db
+	kafkaProvider.kafkaClient = kafkaClient
+	kafkaProvider.kafkaConfig = kafkaConfig
+
+	return kafkaProvider
+}
+
+func (k *KafkaProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.KafkaTopic{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (k *KafkaProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.KafkaTopic
+	if prevData != nil {
+		oldData, ok = prevData.(models.KafkaTopic)
+		if !ok {
+			err := errors.New("conversion to kafka topic failed")
+			return nil, err
+		}
+	}
+
+	if processed.Partitions > 0 && processed.Partitions < oldData.Partitions {
+		err := fmt.Errorf("partitions of kafka topic %s cannot be decreased from %d to %d", processed.Name, oldData.Partitions, processed.Partitions)
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (k *KafkaProvider) InstallComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	if len(k.kafkaConfig.Brokers) == 0 {
+		return errors.New("no kafka brokers configured")
+	}
+
+	// -1 leaves the partitions and replication factor to the broker defaults
+	topicConfig := kafka.TopicConfig{
+		Topic:             topic.Name,
+		NumPartitions:     -1,
+		ReplicationFactor: -1,
+	}
+	if topic.Partitions > 0 {
+		topicConfig.NumPartitions = topic.Partitions
+	}
+	if topic.ReplicationFactor > 0 {
+		topicConfig.ReplicationFactor = topic.ReplicationFactor
+	}
+	for name, value := range topic.Configs {
+		topicConfig.ConfigEntries = append(topicConfig.ConfigEntries, kafka.ConfigEntry{
+			ConfigName:  name,
+			ConfigValue: value,
+		})
+	}
+
+	output, err := k.kafkaClient.CreateTopics(context.TODO(), &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{topicConfig},
+	})
+	if err != nil {
+		return err
+	}
+	err = output.Errors[topic.Name]
+	if errors.Is(err, kafka.TopicAlreadyExists) {
+		if !topic.Import {
+			return existingComponent(fmt.Errorf("kafka topic %s already exists, set import to manage it", topic.Name))
+		}
+		err = k.UpdateComponent(topic)
+		if err != nil {
+			// the imported topic stays whatever happens to the release
+			return existingComponent(err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("creating kafka topic %s: %w", topic.Name, err)
+	}
+	return nil
+}
+
+func (k *KafkaProvider) UpdateComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	if len(k.kafkaConfig.Brokers) == 0 {
+		return errors.New("no kafka brokers configured")
+	}
+
+	current, err := k.describeTopic(context.TODO(), topic.Name)
+	if err != nil {
+		return err
+	}
+	partitions := len(current.Partitions)
+	replicationFactor := len(current.Partitions[0].Replicas)
+
+	if topic.ReplicationFactor > 0 && topic.ReplicationFactor != replicationFactor {
+		return fmt.Errorf("replication factor of kafka topic %s cannot be changed from %d to %d", topic.Name, replicationFactor, topic.ReplicationFactor)
+	}
+	if topic.Partitions > 0 && topic.Partitions < partitions {
+		return fmt.Errorf("partitions of kafka topic %s cannot be decreased from %d to %d", topic.Name, partitions, topic.Partitions)
+	}
+
+	if topic.Partitions > partitions {
+		output, err := k.kafkaClient.CreatePartitions(context.TODO(), &kafka.CreatePartitionsRequest{
+			Topics: []kafka.TopicPartitionsConfig{
+				{
+					Name:  topic.Name,
+					Count: int32(topic.Partitions),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err = output.Errors[topic.Name]; err != nil {
+			return fmt.Errorf("increasing partitions of kafka topic %s: %w", topic.Name, err)
+		}
+	}
+
+	// imported topics keep their configs until the spec gives some
+	if topic.Import && len(topic.Configs) == 0 {
+		return nil
+	}
+	return k.alterConfigs(context.TODO(), topic)
+}
+
+func (k *KafkaProvider) UninstallComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	// imported topics were there before the controller and outlive it
+	if topic.Import {
+		return nil
+	}
+	if len(k.kafkaConfig.Brokers) == 0 {
+		return errors.New("no kafka brokers configured")
+	}
+
+	output, err := k.kafkaClient.DeleteTopics(context.TODO(), &kafka.DeleteTopicsRequest{
+		Topics: []string{topic.Name},
+	})
+	if err != nil {
+		return err
+	}
+	err = output.Errors[topic.Name]
+	if err != nil && !errors.Is(err, kafka.UnknownTopicOrPartition) {
+		return fmt.Errorf("deleting kafka topic %s: %w", topic.Name, err)
+	}
+	return nil
+}
+
+func (k *KafkaProvider) GetStatus(topicInterface interface{}) (responses.ComponentStatus, error) {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: topic.Name,
+	}
+
+	if len(k.kafkaConfig.Brokers) == 0 {
+		return status, errors.New("no kafka brokers configured")
+	}
+
+	current, err := k.describeTopic(context.TODO(), topic.Name)
+	if err != nil {
+		return status, err
+	}
+
+	detail := responses.KafkaStatus{
+		Name:              topic.Name,
+		Partitions:        len(current.Partitions),
+		ReplicationFactor: len(current.Partitions[0].Replicas),
+	}
+	for _, partition := range current.Partitions {
+		if partition.Leader.ID < 0 {
+			detail.OfflinePartitions++
+		}
+		if len(partition.Isr) < len(partition.Replicas) {
+			detail.UnderReplicated++
+		}
+	}
+
+	status.Detail = detail
+	switch {
+	case detail.OfflinePartitions > 0:
+		status.Status = "offline"
+		status.Message = fmt.Sprintf("%d partitions have no leader", detail.OfflinePartitions)
+	case detail.UnderReplicated > 0:
+		status.Healthy = true
+		status.Status = "under-replicated"
+		status.Message = fmt.Sprintf("%d partitions are under-replicated", detail.UnderReplicated)
+	default:
+		status.Healthy = true
+		status.Status = "available"
+	}
+	return status, nil
+}
+
+func (k *KafkaProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := k.database.Model(&models.KafkaTopic{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (k *KafkaProvider) Add(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	result := k.database.Create(&topic)
+	return result.Error
+}
+
+func (k *KafkaProvider) Remove(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	result := k.database.Delete(&models.KafkaTopic{}, "name = ?", topic.Name)
+	return result.Error
+}
+
+func (k *KafkaProvider) Update(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return err
+	}
+
+	return updateByName(k.database, &topic, topic.Name, kafkaConfigColumns)
+}
+
+func (k *KafkaProvider) GetDetail(releaseName string) (interface{}, error) {
+	var topic models.KafkaTopic
+	result := k.database.Where("name = ?", releaseName).First(&topic)
+	return topic, result.Error
+}
+
+func (k *KafkaProvider) GetDetailFromComponent(topicInterface interface{}) (interface{}, error) {
+	topic, ok := topicInterface.(models.KafkaTopic)
+	if !ok {
+		err := errors.New("conversion to kafka topic failed")
+		return nil, err
+	}
+
+	return k.GetDetail(topic.Name)
+}
+
+func (k *KafkaProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var topics []models.KafkaTopic
+	result := k.database.Where("module_release_id = ?", ModuleReleaseID).Find(&topics)
+
+	var topicInterface []interface{} = make([]interface{}, len(topics))
+	for i, v := range topics {
+		topicInterface[i] = v
+	}
+
+	return topicInterface, result.Error
+}
+
+func (k *KafkaProvider) describeTopic(ctx context.Context, name string) (kafka.Topic, error) {
+	output, err := k.kafkaClient.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: []string{name},
+	})
+	if err != nil {
+		return kafka.Topic{}, err
+	}
+	for _, topic := range output.Topics {
+		if topic.Name != name {
+			continue
+		}
+		if topic.Error != nil {
+			return kafka.Topic{}, fmt.Errorf("describing kafka topic %s: %w", name, topic.Error)
+		}
+		if len(topic.Partitions) == 0 {
+			return kafka.Topic{}, fmt.Errorf("kafka topic %s has no partitions", name)
+		}
+		return topic, nil
+	}
+	return kafka.Topic{}, fmt.Errorf("kafka topic %s not found", name)
+}
+
+// alterConfigs replaces the configs of the topic, so configs removed from the
+// spec fall back to the broker defaults.
+func (k *KafkaProvider) alterConfigs(ctx context.Context, topic models.KafkaTopic) error {
+	resource := kafka.AlterConfigRequestResource{
+		ResourceType: kafka.ResourceTypeTopic,
+		ResourceName: topic.Name,
+	}
+	for name, value := range topic.Configs {
+		resource.Configs = append(resource.Configs, kafka.AlterConfigRequestConfig{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	output, err := k.kafkaClient.AlterConfigs(ctx, &kafka.AlterConfigsRequest{
+		Resources: []kafka.AlterConfigRequestResource{resource},
+	})
+	if err != nil {
+		return err
+	}
+	for _, err := range output.Errors {
+		if err != nil {
+			return fmt.Errorf("altering configs of kafka topic %s: %w", topic.Name, err)
+		}
+	}
+	return nil
+}
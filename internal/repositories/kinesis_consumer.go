@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// reconcileConsumers registers the enhanced fan-out consumers declared on the
+// stream and deregisters the ones the controller registered earlier but are no
+// longer declared. Consumers registered outside of the controller are adopted
+// when declared and left alone otherwise.
+func (k *KinesisProvider) reconcileConsumers(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, streamARN *string) error {
+	existing, err := k.listConsumers(ctx, client, streamARN)
+	if err != nil {
+		return err
+	}
+
+	var recorded []models.KinesisConsumer
+	result := k.database.Where("stream_name = ? AND account = ? AND region = ?", kinesisData.Name, kinesisData.Account, kinesisData.Region).Find(&recorded)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	desired := map[string]bool{}
+	for _, name := range kinesisData.Consumers {
+		desired[name] = true
+
+		arn, ok := existing[name]
+		if !ok {
+			arn, err = k.registerConsumer(ctx, client, streamARN, name)
+			if err != nil {
+				return err
+			}
+		}
+		err = k.recordConsumer(kinesisData, name, arn)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, consumer := range recorded {
+		if desired[consumer.Name] {
+			continue
+		}
+		err = k.deregisterConsumer(ctx, client, consumer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KinesisProvider) listConsumers(ctx context.Context, client *kinesis.Client, streamARN *string) (map[string]string, error) {
+	consumers := map[string]string{}
+	input := kinesis.ListStreamConsumersInput{
+		StreamARN: streamARN,
+	}
+	for {
+		output, err := client.ListStreamConsumers(ctx, &input)
+		if err != nil {
+			return nil, err
+		}
+		for _, consumer := range output.Consumers {
+			consumers[*consumer.ConsumerName] = *consumer.ConsumerARN
+		}
+		if output.NextToken == nil {
+			return consumers, nil
+		}
+		input = kinesis.ListStreamConsumersInput{
+			NextToken: output.NextToken,
+		}
+	}
+}
+
+func (k *KinesisProvider) registerConsumer(ctx context.Context, client *kinesis.Client, streamARN *string, name string) (string, error) {
+	input := kinesis.RegisterStreamConsumerInput{
+		StreamARN:    streamARN,
+		ConsumerName: &name,
+	}
+	output, err := client.RegisterStreamConsumer(ctx, &input)
+	if err != nil {
+		return "", fmt.Errorf("registering kinesis consumer %s: %w", name, err)
+	}
+
+	arn := *output.Consumer.ConsumerARN
+	deadline := time.Now().Add(k.kinesisConfig.WaitTimeout)
+	for {
+		describeInput := kinesis.DescribeStreamConsumerInput{
+			ConsumerARN: &arn,
+		}
+		describeOutput, err := client.DescribeStreamConsumer(ctx, &describeInput)
+		if err != nil {
+			return "", err
+		}
+		status := describeOutput.ConsumerDescription.ConsumerStatus
+		if status == types.ConsumerStatusActive {
+			return arn, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("kinesis consumer %s is still %s after %s", name, status, k.kinesisConfig.WaitTimeout)
+		}
+		time.Sleep(k.kinesisConfig.PollInterval)
+	}
+}
+
+func (k *KinesisProvider) deregisterConsumer(ctx context.Context, client *kinesis.Client, consumer models.KinesisConsumer) error {
+	input := kinesis.DeregisterStreamConsumerInput{
+		ConsumerARN: &consumer.ARN,
+	}
+	_, err := client.DeregisterStreamConsumer(ctx, &input)
+	var notFound *types.ResourceNotFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return fmt.Errorf("deregistering kinesis consumer %s: %w", consumer.Name, err)
+	}
+
+	result := k.database.Unscoped().Delete(&consumer)
+	return result.Error
+}
+
+func (k *KinesisProvider) recordConsumer(kinesisData models.Kinesis, name string, arn string) error {
+	consumer := models.KinesisConsumer{
+		StreamName: kinesisData.Name,
+		Account:    kinesisData.Account,
+		Region:     kinesisData.Region,
+		Name:       name,
+		ARN:        arn,
+	}
+	result := k.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stream_name"}, {Name: "account"}, {Name: "region"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"arn", "updated_at"}),
+	}).Create(&consumer)
+	return result.Error
+}
+
+// GetOutputs exposes the consumer ARNs of the stream to the other components
+// of the module as "{stream}/{consumer}".
+func (k *KinesisProvider) GetOutputs(kinesisInterface interface{}) (map[string]string, error) {
+	kinesisData, ok := kinesisInterface.(models.Kinesis)
+	if !ok {
+		err := errors.New("conversion to kinesis failed")
+		return nil, err
+	}
+
+	var consumers []models.KinesisConsumer
+	result := k.database.Where("stream_name = ? AND account = ? AND region = ?", kinesisData.Name, kinesisData.Account, kinesisData.Region).Find(&consumers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	outputs := map[string]string{}
+	for _, consumer := range consumers {
+		outputs[kinesisData.Name+"/"+consumer.Name] = consumer.ARN
+	}
+	return outputs, nil
+}
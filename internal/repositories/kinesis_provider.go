@@ -4,26 +4,46 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
 	"gorm.io/gorm"
 )
 
+type IKinesisProvider interface {
+	Providers
+	GetStreamDetail(string, string, string) (models.Kinesis, error)
+	Import(string, string, string) (models.Kinesis, error)
+}
+
 type KinesisProvider struct {
-	database *gorm.DB
-	kinesis  *kinesis.Client
+	database      *gorm.DB
+	awsConfig     configs.AWSConfig
+	kinesisConfig configs.KinesisConfig
 }
 
-func InitKinesisProvider(db *gorm.DB, kinesis *kinesis.Client) Providers {
+func InitKinesisProvider(db *gorm.DB, awsConfig configs.AWSConfig, kinesisConfig configs.KinesisConfig) IKinesisProvider {
 	kinesisProvider := &KinesisProvider{}
 	kinesisProvider.database = db
-	kinesisProvider.kinesis = kinesis
+	kinesisProvider.awsConfig = awsConfig
+	kinesisProvider.kinesisConfig = kinesisConfig
 
 	return kinesisProvider
 }
 
+func (k *KinesisProvider) kinesisClient(account string, region string) (*kinesis.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(k.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return kinesis.NewFromConfig(cfg), nil
+}
+
 func (k *KinesisProvider) Convert(rawData interface{}) (interface{}, error) {
 	jsonStr, err := json.Marshal(rawData)
 	if err != nil {
@@ -70,12 +90,30 @@ func (k *KinesisProvider) InstallComponent(kinesisInterface interface{}) error {
 		return err
 	}
 
+	err := validateKinesis(kinesisData)
+	if err != nil {
+		return err
+	}
+
+	client, err := k.kinesisClient(kinesisData.Account, kinesisData.Region)
+	if err != nil {
+		return err
+	}
+
 	input := kinesis.CreateStreamInput{
 		StreamName: &kinesisData.Name,
-		ShardCount: &kinesisData.Shards,
 	}
-	_, err := k.kinesis.CreateStream(context.TODO(), &input)
-	return err
+	if kinesisData.StreamMode == models.KinesisStreamModeOnDemand {
+		input.StreamModeDetails = &types.StreamModeDetails{StreamMode: types.StreamModeOnDemand}
+	} else {
+		input.ShardCount = &kinesisData.Shards
+	}
+	_, err = client.CreateStream(context.TODO(), &input)
+	if err != nil {
+		return err
+	}
+
+	return k.reconcileStream(context.TODO(), client, kinesisData)
 }
 
 func (k *KinesisProvider) UpdateComponent(kinesisInterface interface{}) error {
@@ -85,14 +123,17 @@ func (k *KinesisProvider) UpdateComponent(kinesisInterface interface{}) error {
 		return err
 	}
 
-	input := kinesis.UpdateShardCountInput{
-		ScalingType:      types.ScalingTypeUniformScaling,
-		StreamName:       &kinesisData.Name,
-		TargetShardCount: &kinesisData.Shards,
+	err := validateKinesis(kinesisData)
+	if err != nil {
+		return err
 	}
 
-	_, err := k.kinesis.UpdateShardCount(context.TODO(), &input)
-	return err
+	client, err := k.kinesisClient(kinesisData.Account, kinesisData.Region)
+	if err != nil {
+		return err
+	}
+
+	return k.reconcileStream(context.TODO(), client, kinesisData)
 }
 
 func (k *KinesisProvider) UninstallComponent(kinesisInterface interface{}) error {
@@ -101,14 +142,71 @@ func (k *KinesisProvider) UninstallComponent(kinesisInterface interface{}) error
 		err := errors.New("conversion to kinesis failed")
 		return err
 	}
+	client, err := k.kinesisClient(kinesisData.Account, kinesisData.Region)
+	if err != nil {
+		return err
+	}
+
+	var consumers []models.KinesisConsumer
+	result := k.database.Where("stream_name = ? AND account = ? AND region = ?", kinesisData.Name, kinesisData.Account, kinesisData.Region).Find(&consumers)
+	if result.Error != nil {
+		return result.Error
+	}
+	for _, consumer := range consumers {
+		err = k.deregisterConsumer(context.TODO(), client, consumer)
+		if err != nil {
+			return err
+		}
+	}
+
 	input := kinesis.DeleteStreamInput{
 		StreamName: &kinesisData.Name,
 	}
-	_, err := k.kinesis.DeleteStream(context.TODO(), &input)
+	_, err = client.DeleteStream(context.TODO(), &input)
 	return err
 
 }
 
+func (k *KinesisProvider) GetStatus(kinesisInterface interface{}) (responses.ComponentStatus, error) {
+	kinesisData, ok := kinesisInterface.(models.Kinesis)
+	if !ok {
+		err := errors.New("conversion to kinesis failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: kinesisData.Name,
+	}
+
+	client, err := k.kinesisClient(kinesisData.Account, kinesisData.Region)
+	if err != nil {
+		return status, err
+	}
+
+	input := kinesis.DescribeStreamSummaryInput{
+		StreamName: &kinesisData.Name,
+	}
+	output, err := client.DescribeStreamSummary(context.TODO(), &input)
+	if err != nil {
+		return status, err
+	}
+
+	summary := output.StreamDescriptionSummary
+	kinesisStatus := responses.KinesisStatus{
+		Name:                 kinesisData.Name,
+		Status:               string(summary.StreamStatus),
+		OpenShardCount:       *summary.OpenShardCount,
+		RetentionPeriodHours: *summary.RetentionPeriodHours,
+	}
+	if summary.ConsumerCount != nil {
+		kinesisStatus.ConsumerCount = *summary.ConsumerCount
+	}
+
+	status.Healthy = summary.StreamStatus == types.StreamStatusActive
+	status.Status = kinesisStatus.Status
+	status.Detail = kinesisStatus
+	return status, nil
+}
+
 func (k *KinesisProvider) GetAllName() ([]string, error) {
 	var names []string
 	result := k.database.Model(&models.Kinesis{}).Pluck("name", &names)
@@ -134,7 +232,14 @@ func (k *KinesisProvider) Remove(kinesisInterface interface{}) error {
 		return err
 	}
 
-	result := k.database.Delete(&models.Kinesis{}, "name = ?", kinesis.Name)
+	// unscoped, a soft deleted row would keep the name taken in its account
+	// and region
+	result := k.database.Unscoped().Delete(&models.Kinesis{}, "name = ? AND account = ? AND region = ?", kinesis.Name, kinesis.Account, kinesis.Region)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = k.database.Unscoped().Delete(&models.KinesisConsumer{}, "stream_name = ? AND account = ? AND region = ?", kinesis.Name, kinesis.Account, kinesis.Region)
 	return result.Error
 }
 
@@ -145,12 +250,35 @@ func (k *KinesisProvider) Update(kinesisInterface interface{}) error {
 		return err
 	}
 
-	result := k.database.Model(&kinesis).Where("name = ?", kinesis.Name).Updates(kinesis)
-	return result.Error
+	conditions := map[string]interface{}{
+		"name":    kinesis.Name,
+		"account": kinesis.Account,
+		"region":  kinesis.Region,
+	}
+	return updateWhere(k.database, &kinesis, conditions, kinesisConfigColumns)
 }
+
+// GetDetail finds the stream by name alone, which fails when streams of
+// several accounts or regions share the name.
 func (k *KinesisProvider) GetDetail(releaseName string) (interface{}, error) {
+	var streams []models.Kinesis
+	result := k.database.Where("name = ?", releaseName).Limit(2).Find(&streams)
+	if result.Error != nil {
+		return models.Kinesis{}, result.Error
+	}
+	switch len(streams) {
+	case 0:
+		return models.Kinesis{}, gorm.ErrRecordNotFound
+	case 1:
+		return streams[0], nil
+	default:
+		return models.Kinesis{}, fmt.Errorf("kinesis streams named %s exist in several accounts or regions, give the account and region", releaseName)
+	}
+}
+
+func (k *KinesisProvider) GetStreamDetail(name string, account string, region string) (models.Kinesis, error) {
 	var kinesis models.Kinesis
-	result := k.database.Where("name = ?", releaseName).First(&kinesis)
+	result := k.database.Where("name = ? AND account = ? AND region = ?", name, account, region).First(&kinesis)
 	return kinesis, result.Error
 }
 
@@ -161,7 +289,7 @@ func (k *KinesisProvider) GetDetailFromComponent(kinesisInterface interface{}) (
 		return nil, err
 	}
 
-	return k.GetDetail(kinesis.Name)
+	return k.GetStreamDetail(kinesis.Name, kinesis.Account, kinesis.Region)
 }
 
 func (k *KinesisProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
@@ -175,3 +303,61 @@ func (k *KinesisProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interf
 
 	return kinesisInterface, result.Error
 }
+
+func (k *KinesisProvider) Import(name string, account string, region string) (models.Kinesis, error) {
+	client, err := k.kinesisClient(account, region)
+	if err != nil {
+		return models.Kinesis{}, err
+	}
+
+	input := kinesis.DescribeStreamSummaryInput{
+		StreamName: &name,
+	}
+	output, err := client.DescribeStreamSummary(context.TODO(), &input)
+	if err != nil {
+		return models.Kinesis{}, err
+	}
+
+	summary := output.StreamDescriptionSummary
+	kinesisData := models.Kinesis{
+		Name:                 *summary.StreamName,
+		Account:              account,
+		Region:               region,
+		Shards:               *summary.OpenShardCount,
+		StreamMode:           models.KinesisStreamModeProvisioned,
+		RetentionPeriodHours: *summary.RetentionPeriodHours,
+		Revision:             1,
+	}
+	if summary.StreamModeDetails != nil && summary.StreamModeDetails.StreamMode == types.StreamModeOnDemand {
+		kinesisData.StreamMode = models.KinesisStreamModeOnDemand
+	}
+	if summary.EncryptionType == types.EncryptionTypeKms && summary.KeyId != nil {
+		kinesisData.EncryptionKeyID = *summary.KeyId
+	}
+	for _, monitoring := range summary.EnhancedMonitoring {
+		for _, metric := range monitoring.ShardLevelMetrics {
+			kinesisData.ShardLevelMetrics = append(kinesisData.ShardLevelMetrics, string(metric))
+		}
+	}
+
+	consumers, err := k.listConsumers(context.TODO(), client, summary.StreamARN)
+	if err != nil {
+		return models.Kinesis{}, err
+	}
+	for consumerName, arn := range consumers {
+		kinesisData.Consumers = append(kinesisData.Consumers, consumerName)
+		err = k.recordConsumer(kinesisData, consumerName, arn)
+		if err != nil {
+			return models.Kinesis{}, err
+		}
+	}
+
+	tags, err := k.listTags(context.TODO(), client, name)
+	if err != nil {
+		return models.Kinesis{}, err
+	}
+	if len(tags) > 0 {
+		kinesisData.Tags = tags
+	}
+	return kinesisData, nil
+}
@@ -0,0 +1,391 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+const (
+	// AddTagsToStream and RemoveTagsFromStream accept at most 10 tags per call
+	kinesisTagBatchSize = 10
+	// UpdateShardCount may be called at most 10 times per stream in a rolling 24 hours
+	kinesisMaxReshardSteps = 10
+)
+
+var kinesisConfigColumns = []string{
+	"stream_mode",
+	"retention_period_hours",
+	"encryption_key_id",
+	"shard_level_metrics",
+	"tags",
+	"consumers",
+}
+
+func (k *KinesisProvider) describeStream(ctx context.Context, client *kinesis.Client, name string) (*types.StreamDescriptionSummary, error) {
+	input := kinesis.DescribeStreamSummaryInput{
+		StreamName: &name,
+	}
+	output, err := client.DescribeStreamSummary(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	return output.StreamDescriptionSummary, nil
+}
+
+// waitForActive polls the stream until it is ACTIVE, every configuration
+// change on a stream is rejected while it is CREATING or UPDATING.
+func (k *KinesisProvider) waitForActive(ctx context.Context, client *kinesis.Client, name string) (*types.StreamDescriptionSummary, error) {
+	deadline := time.Now().Add(k.kinesisConfig.WaitTimeout)
+	for {
+		summary, err := k.describeStream(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+		switch summary.StreamStatus {
+		case types.StreamStatusActive:
+			return summary, nil
+		case types.StreamStatusDeleting:
+			return nil, fmt.Errorf("kinesis stream %s is being deleted", name)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("kinesis stream %s is still %s after %s", name, summary.StreamStatus, k.kinesisConfig.WaitTimeout)
+		}
+		time.Sleep(k.kinesisConfig.PollInterval)
+	}
+}
+
+// reconcileStream brings the retention, encryption, stream mode, enhanced
+// monitoring, consumers and tags of an existing stream in line with kinesisData.
+func (k *KinesisProvider) reconcileStream(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis) error {
+	summary, err := k.waitForActive(ctx, client, kinesisData.Name)
+	if err != nil {
+		return err
+	}
+
+	steps := []func(context.Context, *kinesis.Client, models.Kinesis, *types.StreamDescriptionSummary) (bool, error){
+		k.reconcileStreamMode,
+		k.reconcileShardCount,
+		k.reconcileRetention,
+		k.reconcileEncryption,
+		k.reconcileMonitoring,
+	}
+	for _, step := range steps {
+		changed, err := step(ctx, client, kinesisData, summary)
+		if err != nil {
+			return err
+		}
+		if changed {
+			summary, err = k.waitForActive(ctx, client, kinesisData.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = k.reconcileConsumers(ctx, client, kinesisData, summary.StreamARN)
+	if err != nil {
+		return err
+	}
+
+	return k.reconcileTags(ctx, client, kinesisData)
+}
+
+func streamMode(kinesisData models.Kinesis) (types.StreamMode, error) {
+	switch kinesisData.StreamMode {
+	case "", models.KinesisStreamModeProvisioned:
+		return types.StreamModeProvisioned, nil
+	case models.KinesisStreamModeOnDemand:
+		return types.StreamModeOnDemand, nil
+	default:
+		return "", fmt.Errorf("unknown stream mode %s", kinesisData.StreamMode)
+	}
+}
+
+func (k *KinesisProvider) reconcileStreamMode(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, summary *types.StreamDescriptionSummary) (bool, error) {
+	mode, err := streamMode(kinesisData)
+	if err != nil {
+		return false, err
+	}
+
+	current := types.StreamModeProvisioned
+	if summary.StreamModeDetails != nil {
+		current = summary.StreamModeDetails.StreamMode
+	}
+	if current == mode {
+		return false, nil
+	}
+
+	input := kinesis.UpdateStreamModeInput{
+		StreamARN:         summary.StreamARN,
+		StreamModeDetails: &types.StreamModeDetails{StreamMode: mode},
+	}
+	_, err = client.UpdateStreamMode(ctx, &input)
+	return true, err
+}
+
+func (k *KinesisProvider) reconcileShardCount(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, summary *types.StreamDescriptionSummary) (bool, error) {
+	mode, err := streamMode(kinesisData)
+	if err != nil {
+		return false, err
+	}
+	if mode == types.StreamModeOnDemand || kinesisData.Shards == 0 || kinesisData.Shards == *summary.OpenShardCount {
+		return false, nil
+	}
+
+	current := *summary.OpenShardCount
+	steps := reshardSteps(current, kinesisData.Shards)
+	if len(steps) > kinesisMaxReshardSteps {
+		err := fmt.Errorf("resharding kinesis stream %s from %d to %d shards needs %d steps, only %d are allowed per day", kinesisData.Name, current, kinesisData.Shards, len(steps), kinesisMaxReshardSteps)
+		return false, err
+	}
+
+	for i, target := range steps {
+		if i > 0 {
+			_, err := k.waitForActive(ctx, client, kinesisData.Name)
+			if err != nil {
+				return true, err
+			}
+		}
+
+		target := target
+		input := kinesis.UpdateShardCountInput{
+			ScalingType:      types.ScalingTypeUniformScaling,
+			StreamName:       &kinesisData.Name,
+			TargetShardCount: &target,
+		}
+		_, err := client.UpdateShardCount(ctx, &input)
+		if err != nil {
+			return i > 0, fmt.Errorf("resharding kinesis stream %s from %d to %d shards: %w", kinesisData.Name, current, target, err)
+		}
+		current = target
+	}
+	return true, nil
+}
+
+// reshardSteps splits a shard count change into the targets UpdateShardCount
+// accepts, each at most double and at least half of the previous count.
+func reshardSteps(current int32, target int32) []int32 {
+	var steps []int32
+	for current != target {
+		if target > current {
+			current *= 2
+			if current > target {
+				current = target
+			}
+		} else {
+			current = (current + 1) / 2
+			if current < target {
+				current = target
+			}
+		}
+		steps = append(steps, current)
+	}
+	return steps
+}
+
+func (k *KinesisProvider) reconcileRetention(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, summary *types.StreamDescriptionSummary) (bool, error) {
+	desired := kinesisData.RetentionPeriodHours
+	current := *summary.RetentionPeriodHours
+	if desired == 0 || desired == current {
+		return false, nil
+	}
+
+	var err error
+	if desired > current {
+		input := kinesis.IncreaseStreamRetentionPeriodInput{
+			StreamName:           &kinesisData.Name,
+			RetentionPeriodHours: &desired,
+		}
+		_, err = client.IncreaseStreamRetentionPeriod(ctx, &input)
+	} else {
+		input := kinesis.DecreaseStreamRetentionPeriodInput{
+			StreamName:           &kinesisData.Name,
+			RetentionPeriodHours: &desired,
+		}
+		_, err = client.DecreaseStreamRetentionPeriod(ctx, &input)
+	}
+	return true, err
+}
+
+func (k *KinesisProvider) reconcileEncryption(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, summary *types.StreamDescriptionSummary) (bool, error) {
+	currentKey := ""
+	if summary.EncryptionType == types.EncryptionTypeKms && summary.KeyId != nil {
+		currentKey = *summary.KeyId
+	}
+	if currentKey == kinesisData.EncryptionKeyID {
+		return false, nil
+	}
+
+	var err error
+	if kinesisData.EncryptionKeyID == "" {
+		input := kinesis.StopStreamEncryptionInput{
+			StreamName:     &kinesisData.Name,
+			EncryptionType: types.EncryptionTypeKms,
+			KeyId:          &currentKey,
+		}
+		_, err = client.StopStreamEncryption(ctx, &input)
+	} else {
+		input := kinesis.StartStreamEncryptionInput{
+			StreamName:     &kinesisData.Name,
+			EncryptionType: types.EncryptionTypeKms,
+			KeyId:          &kinesisData.EncryptionKeyID,
+		}
+		_, err = client.StartStreamEncryption(ctx, &input)
+	}
+	return true, err
+}
+
+func (k *KinesisProvider) reconcileMonitoring(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis, summary *types.StreamDescriptionSummary) (bool, error) {
+	current := map[types.MetricsName]bool{}
+	for _, monitoring := range summary.EnhancedMonitoring {
+		for _, metric := range monitoring.ShardLevelMetrics {
+			current[metric] = true
+		}
+	}
+	desired := map[types.MetricsName]bool{}
+	for _, metric := range kinesisData.ShardLevelMetrics {
+		desired[types.MetricsName(metric)] = true
+	}
+
+	var enable, disable []types.MetricsName
+	for metric := range desired {
+		if !current[metric] {
+			enable = append(enable, metric)
+		}
+	}
+	for metric := range current {
+		if !desired[metric] {
+			disable = append(disable, metric)
+		}
+	}
+
+	if len(disable) > 0 {
+		input := kinesis.DisableEnhancedMonitoringInput{
+			StreamName:        &kinesisData.Name,
+			ShardLevelMetrics: disable,
+		}
+		_, err := client.DisableEnhancedMonitoring(ctx, &input)
+		if err != nil {
+			return true, err
+		}
+	}
+	if len(enable) > 0 {
+		input := kinesis.EnableEnhancedMonitoringInput{
+			StreamName:        &kinesisData.Name,
+			ShardLevelMetrics: enable,
+		}
+		_, err := client.EnableEnhancedMonitoring(ctx, &input)
+		if err != nil {
+			return true, err
+		}
+	}
+	return len(enable) > 0 || len(disable) > 0, nil
+}
+
+func (k *KinesisProvider) listTags(ctx context.Context, client *kinesis.Client, name string) (map[string]string, error) {
+	tags := map[string]string{}
+	input := kinesis.ListTagsForStreamInput{
+		StreamName: &name,
+	}
+	for {
+		output, err := client.ListTagsForStream(ctx, &input)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range output.Tags {
+			value := ""
+			if tag.Value != nil {
+				value = *tag.Value
+			}
+			tags[*tag.Key] = value
+		}
+		if output.HasMoreTags == nil || !*output.HasMoreTags || len(output.Tags) == 0 {
+			return tags, nil
+		}
+		input.ExclusiveStartTagKey = output.Tags[len(output.Tags)-1].Key
+	}
+}
+
+// reconcileTags makes the stream tags match the spec. Streams whose spec does
+// not set tags keep the tags they have.
+func (k *KinesisProvider) reconcileTags(ctx context.Context, client *kinesis.Client, kinesisData models.Kinesis) error {
+	if kinesisData.Tags == nil {
+		return nil
+	}
+
+	current, err := k.listTags(ctx, client, kinesisData.Name)
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	for key := range current {
+		if _, ok := kinesisData.Tags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	added := map[string]string{}
+	for key, value := range kinesisData.Tags {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			added[key] = value
+		}
+	}
+
+	for start := 0; start < len(removed); start += kinesisTagBatchSize {
+		end := start + kinesisTagBatchSize
+		if end > len(removed) {
+			end = len(removed)
+		}
+		input := kinesis.RemoveTagsFromStreamInput{
+			StreamName: &kinesisData.Name,
+			TagKeys:    removed[start:end],
+		}
+		_, err := client.RemoveTagsFromStream(ctx, &input)
+		if err != nil {
+			return err
+		}
+	}
+
+	batch := map[string]string{}
+	for key, value := range added {
+		batch[key] = value
+		if len(batch) == kinesisTagBatchSize {
+			err := k.addTags(ctx, client, kinesisData.Name, batch)
+			if err != nil {
+				return err
+			}
+			batch = map[string]string{}
+		}
+	}
+	if len(batch) > 0 {
+		return k.addTags(ctx, client, kinesisData.Name, batch)
+	}
+	return nil
+}
+
+func (k *KinesisProvider) addTags(ctx context.Context, client *kinesis.Client, name string, tags map[string]string) error {
+	input := kinesis.AddTagsToStreamInput{
+		StreamName: &name,
+		Tags:       tags,
+	}
+	_, err := client.AddTagsToStream(ctx, &input)
+	return err
+}
+
+func validateKinesis(kinesisData models.Kinesis) error {
+	mode, err := streamMode(kinesisData)
+	if err != nil {
+		return err
+	}
+	if mode == types.StreamModeProvisioned && kinesisData.Shards <= 0 {
+		err := errors.New("provisioned kinesis stream requires a positive shard count")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReshardSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int32
+		target  int32
+		want    []int32
+	}{
+		{name: "unchanged", current: 4, target: 4, want: nil},
+		{name: "up within double", current: 4, target: 6, want: []int32{6}},
+		{name: "up to double", current: 4, target: 8, want: []int32{8}},
+		{name: "up past double", current: 1, target: 10, want: []int32{2, 4, 8, 10}},
+		{name: "down within half", current: 8, target: 5, want: []int32{5}},
+		{name: "down to half", current: 8, target: 4, want: []int32{4}},
+		{name: "down past half", current: 10, target: 1, want: []int32{5, 3, 2, 1}},
+		{name: "odd count rounds up", current: 3, target: 1, want: []int32{2, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := reshardSteps(test.current, test.target)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("reshardSteps(%d, %d) = %v, want %v", test.current, test.target, got, test.want)
+			}
+		})
+	}
+}
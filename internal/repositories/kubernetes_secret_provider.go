@@ -0,0 +1,414 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	kubernetesSecretManagedBy = "app.kubernetes.io/managed-by"
+	kubernetesSecretManager   = "warehouse-controller"
+)
+
+var kubernetesSecretConfigColumns = []string{
+	"namespace",
+	"data",
+	"labels",
+}
+
+type KubernetesSecretProvider struct {
+	database           *gorm.DB
+	clientset          kubernetes.Interface
+	defaultNamespace   string
+	availableNamespace map[string]bool
+	secretProviders    map[string]SecretProviders
+}
+
+func InitKubernetesSecretProvider(db *gorm.DB, restConfig *rest.Config, defaultNamespace string, availableNamespace []string, secretProviders map[string]SecretProviders) (Providers, error) {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetesSecretProvider := &KubernetesSecretProvider{}
+	kubernetesSecretProvider.database = db
+	kubernetesSecretProvider.clientset = clientset
+	kubernetesSecretProvider.defaultNamespace = defaultNamespace
+	kubernetesSecretProvider.availableNamespace = map[string]bool{}
+	for _, namespace := range availableNamespace {
+		kubernetesSecretProvider.availableNamespace[namespace] = true
+	}
+	kubernetesSecretProvider.secretProviders = secretProviders
+
+	return kubernetesSecretProvider, nil
+}
+
+func (k *KubernetesSecretProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.KubernetesSecret{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	if component.Namespace == "" {
+		component.Namespace = k.defaultNamespace
+	}
+	if component.Type == "" {
+		component.Type = string(corev1.SecretTypeOpaque)
+	}
+	return component, nil
+}
+
+func (k *KubernetesSecretProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.KubernetesSecret
+	if prevData != nil {
+		oldData, ok = prevData.(models.KubernetesSecret)
+		if !ok {
+			err := errors.New("conversion to kubernetes secret failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (k *KubernetesSecretProvider) InstallComponent(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	return k.apply(context.TODO(), secret)
+}
+
+// UpdateComponent applies the secret and deletes the one of the previous
+// revision when the namespace changed.
+func (k *KubernetesSecretProvider) UpdateComponent(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	// the stored component still holds the previous revision
+	oldInterface, err := k.GetDetail(secret.Name)
+	if err != nil {
+		return err
+	}
+	oldSecret := oldInterface.(models.KubernetesSecret)
+
+	err = k.apply(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+	if oldSecret.Namespace != secret.Namespace {
+		return k.delete(context.TODO(), oldSecret)
+	}
+	return nil
+}
+
+func (k *KubernetesSecretProvider) UninstallComponent(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	return k.delete(context.TODO(), secret)
+}
+
+func (k *KubernetesSecretProvider) GetStatus(secretInterface interface{}) (responses.ComponentStatus, error) {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: secret.Name,
+	}
+
+	current, err := k.clientset.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		status.Status = "missing"
+		status.Message = fmt.Sprintf("secret %s does not exist in namespace %s", secret.Name, secret.Namespace)
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	data, err := k.resolve(secret)
+	if err != nil {
+		return status, err
+	}
+
+	detail := responses.KubernetesSecretStatus{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(current.Type),
+	}
+	for _, key := range sortedKeys(data) {
+		value, ok := current.Data[key]
+		if !ok {
+			detail.MissingKeys = append(detail.MissingKeys, key)
+			continue
+		}
+		if !bytes.Equal(value, data[key]) {
+			detail.OutdatedKeys = append(detail.OutdatedKeys, key)
+		}
+	}
+
+	status.Detail = detail
+	if len(detail.MissingKeys)+len(detail.OutdatedKeys) > 0 {
+		status.Status = "outdated"
+		status.Message = "some keys of the secret differ from the secret providers"
+		return status, nil
+	}
+	status.Healthy = true
+	status.Status = "synced"
+	return status, nil
+}
+
+// GetOutputs exposes the name and namespace of the secret, which also makes
+// the module release it before the charts that mount it.
+func (k *KubernetesSecretProvider) GetOutputs(secretInterface interface{}) (map[string]string, error) {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return nil, err
+	}
+
+	outputs := map[string]string{
+		secret.Name + "/name":      secret.Name,
+		secret.Name + "/namespace": secret.Namespace,
+	}
+	return outputs, nil
+}
+
+func (k *KubernetesSecretProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := k.database.Model(&models.KubernetesSecret{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (k *KubernetesSecretProvider) Add(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	result := k.database.Create(&secret)
+	return result.Error
+}
+
+func (k *KubernetesSecretProvider) Remove(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	result := k.database.Delete(&models.KubernetesSecret{}, "name = ?", secret.Name)
+	return result.Error
+}
+
+func (k *KubernetesSecretProvider) Update(secretInterface interface{}) error {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return err
+	}
+
+	return updateByName(k.database, &secret, secret.Name, kubernetesSecretConfigColumns)
+}
+
+func (k *KubernetesSecretProvider) GetDetail(releaseName string) (interface{}, error) {
+	var secret models.KubernetesSecret
+	result := k.database.Where("name = ?", releaseName).First(&secret)
+	return secret, result.Error
+}
+
+func (k *KubernetesSecretProvider) GetDetailFromComponent(secretInterface interface{}) (interface{}, error) {
+	secret, ok := secretInterface.(models.KubernetesSecret)
+	if !ok {
+		err := errors.New("conversion to kubernetes secret failed")
+		return nil, err
+	}
+
+	return k.GetDetail(secret.Name)
+}
+
+func (k *KubernetesSecretProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var secrets []models.KubernetesSecret
+	result := k.database.Where("module_release_id = ?", ModuleReleaseID).Find(&secrets)
+
+	var secretInterface []interface{} = make([]interface{}, len(secrets))
+	for i, v := range secrets {
+		secretInterface[i] = v
+	}
+
+	return secretInterface, result.Error
+}
+
+// apply creates the secret or updates it when its data or labels changed.
+// Secrets that exist but were not created by the controller are left alone.
+func (k *KubernetesSecretProvider) apply(ctx context.Context, secret models.KubernetesSecret) error {
+	if !k.availableNamespace[secret.Namespace] {
+		return fmt.Errorf("unknown namespace %s for secret %s", secret.Namespace, secret.Name)
+	}
+	data, err := k.resolve(secret)
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{}
+	for key, value := range secret.Labels {
+		labels[key] = value
+	}
+	labels[kubernetesSecretManagedBy] = kubernetesSecretManager
+
+	secrets := k.clientset.CoreV1().Secrets(secret.Namespace)
+	current, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: secret.Namespace,
+				Labels:    labels,
+			},
+			Type: corev1.SecretType(secret.Type),
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if current.Labels[kubernetesSecretManagedBy] != kubernetesSecretManager {
+		return fmt.Errorf("secret %s already exists in namespace %s and is not managed by the controller", secret.Name, secret.Namespace)
+	}
+	if string(current.Type) != secret.Type {
+		return fmt.Errorf("secret %s has type %s, the type of a secret cannot be changed", secret.Name, current.Type)
+	}
+	if sameSecretData(current.Data, data) && sameLabels(current.Labels, labels) {
+		return nil
+	}
+
+	current.Labels = labels
+	current.Data = data
+	current.StringData = nil
+	_, err = secrets.Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// delete removes the secret when the controller manages it. A secret of the
+// same name without the managed-by label, such as one that made the install
+// fail, belongs to someone else and is left alone.
+func (k *KubernetesSecretProvider) delete(ctx context.Context, secret models.KubernetesSecret) error {
+	secrets := k.clientset.CoreV1().Secrets(secret.Namespace)
+	current, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.Labels[kubernetesSecretManagedBy] != kubernetesSecretManager {
+		return nil
+	}
+
+	// the uid precondition keeps a secret recreated in between
+	err = secrets.Delete(ctx, secret.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &current.UID},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// resolve reads every key of the secret from the secret providers.
+func (k *KubernetesSecretProvider) resolve(secret models.KubernetesSecret) (map[string][]byte, error) {
+	if len(secret.Data) == 0 {
+		return nil, fmt.Errorf("secret %s needs at least one key", secret.Name)
+	}
+	data := map[string][]byte{}
+	for key, reference := range secret.Data {
+		value, err := resolveSecret(k.secretProviders, reference)
+		if err != nil {
+			return nil, fmt.Errorf("key %s of secret %s: %w", key, secret.Name, err)
+		}
+		data[key] = []byte(value)
+	}
+	return data, nil
+}
+
+func sameSecretData(current map[string][]byte, desired map[string][]byte) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for key, value := range desired {
+		currentValue, ok := current[key]
+		if !ok || !bytes.Equal(currentValue, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameLabels(current map[string]string, desired map[string]string) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for key, value := range desired {
+		currentValue, ok := current[key]
+		if !ok || currentValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeleteKubernetesSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		missing     bool
+		wantDeleted bool
+	}{
+		{name: "managed", labels: map[string]string{kubernetesSecretManagedBy: kubernetesSecretManager}, wantDeleted: true},
+		{name: "without labels"},
+		{name: "managed by another tool", labels: map[string]string{kubernetesSecretManagedBy: "helm"}},
+		{name: "missing", missing: true, wantDeleted: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if !test.missing {
+				_, err := clientset.CoreV1().Secrets("data").Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "data", Labels: test.labels},
+				}, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			provider := &KubernetesSecretProvider{clientset: clientset}
+
+			err := provider.delete(context.TODO(), models.KubernetesSecret{Name: "credentials", Namespace: "data"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = clientset.CoreV1().Secrets("data").Get(context.TODO(), "credentials", metav1.GetOptions{})
+			if deleted := apierrors.IsNotFound(err); deleted != test.wantDeleted {
+				t.Errorf("got deleted %v, want %v", deleted, test.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+const manifestFieldManager = "warehouse-controller"
+
+var manifestConfigColumns = []string{
+	"namespace",
+	"manifests",
+}
+
+type ManifestProvider struct {
+	database           *gorm.DB
+	dynamicClient      dynamic.Interface
+	mapper             *restmapper.DeferredDiscoveryRESTMapper
+	defaultNamespace   string
+	availableNamespace map[string]bool
+	clusterKinds       map[string]bool
+}
+
+func InitManifestProvider(db *gorm.DB, restConfig *rest.Config, defaultNamespace string, availableNamespace []string, clusterKinds []string) (Providers, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestProvider := &ManifestProvider{}
+	manifestProvider.database = db
+	manifestProvider.dynamicClient = dynamicClient
+	manifestProvider.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	manifestProvider.defaultNamespace = defaultNamespace
+	manifestProvider.availableNamespace = map[string]bool{}
+	for _, namespace := range availableNamespace {
+		manifestProvider.availableNamespace[namespace] = true
+	}
+	manifestProvider.clusterKinds = map[string]bool{}
+	for _, kind := range clusterKinds {
+		manifestProvider.clusterKinds[kind] = true
+	}
+
+	return manifestProvider, nil
+}
+
+func (m *ManifestProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Manifest{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	if component.Namespace == "" {
+		component.Namespace = m.defaultNamespace
+	}
+	return component, nil
+}
+
+func (m *ManifestProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Manifest
+	if prevData != nil {
+		oldData, ok = prevData.(models.Manifest)
+		if !ok {
+			err := errors.New("conversion to manifest failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (m *ManifestProvider) InstallComponent(manifestInterface interface{}) error {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return err
+	}
+
+	return m.apply(context.TODO(), manifest)
+}
+
+func (m *ManifestProvider) UpdateComponent(manifestInterface interface{}) error {
+	return m.InstallComponent(manifestInterface)
+}
+
+func (m *ManifestProvider) UninstallComponent(manifestInterface interface{}) error {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return err
+	}
+
+	inventory, err := m.getInventory(manifest.Name)
+	if err != nil {
+		return err
+	}
+	for _, object := range inventory {
+		err = m.deleteObject(context.TODO(), object)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ManifestProvider) GetStatus(manifestInterface interface{}) (responses.ComponentStatus, error) {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: manifest.Name,
+	}
+
+	inventory, err := m.getInventory(manifest.Name)
+	if err != nil {
+		return status, err
+	}
+
+	detail := responses.ManifestStatus{
+		Name: manifest.Name,
+	}
+	missing := 0
+	for _, object := range inventory {
+		objectStatus := responses.ManifestObjectStatus{
+			APIVersion: schema.GroupVersion{Group: object.Group, Version: object.Version}.String(),
+			Kind:       object.Kind,
+			Namespace:  object.Namespace,
+			Name:       object.Name,
+		}
+
+		resource, err := m.resourceInterface(object.Group, object.Version, object.Kind, object.Namespace)
+		if err != nil {
+			return status, err
+		}
+		_, err = resource.Get(context.TODO(), object.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return status, err
+		}
+		objectStatus.Exists = err == nil
+		if !objectStatus.Exists {
+			missing++
+		}
+		detail.Objects = append(detail.Objects, objectStatus)
+	}
+
+	status.Detail = detail
+	if missing > 0 {
+		status.Status = "missing"
+		status.Message = fmt.Sprintf("%d of %d objects are missing", missing, len(inventory))
+		return status, nil
+	}
+	status.Healthy = true
+	status.Status = "applied"
+	return status, nil
+}
+
+func (m *ManifestProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := m.database.Model(&models.Manifest{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (m *ManifestProvider) Add(manifestInterface interface{}) error {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return err
+	}
+
+	result := m.database.Create(&manifest)
+	return result.Error
+}
+
+func (m *ManifestProvider) Remove(manifestInterface interface{}) error {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return err
+	}
+
+	result := m.database.Unscoped().Delete(&models.ManifestObject{}, "manifest_name = ?", manifest.Name)
+	if result.Error != nil {
+		return result.Error
+	}
+	result = m.database.Delete(&models.Manifest{}, "name = ?", manifest.Name)
+	return result.Error
+}
+
+func (m *ManifestProvider) Update(manifestInterface interface{}) error {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return err
+	}
+
+	return updateByName(m.database, &manifest, manifest.Name, manifestConfigColumns)
+}
+
+func (m *ManifestProvider) GetDetail(releaseName string) (interface{}, error) {
+	var manifest models.Manifest
+	result := m.database.Where("name = ?", releaseName).First(&manifest)
+	return manifest, result.Error
+}
+
+func (m *ManifestProvider) GetDetailFromComponent(manifestInterface interface{}) (interface{}, error) {
+	manifest, ok := manifestInterface.(models.Manifest)
+	if !ok {
+		err := errors.New("conversion to manifest failed")
+		return nil, err
+	}
+
+	return m.GetDetail(manifest.Name)
+}
+
+func (m *ManifestProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var manifests []models.Manifest
+	result := m.database.Where("module_release_id = ?", ModuleReleaseID).Find(&manifests)
+
+	var manifestInterface []interface{} = make([]interface{}, len(manifests))
+	for i, v := range manifests {
+		manifestInterface[i] = v
+	}
+
+	return manifestInterface, result.Error
+}
+
+// apply server-side applies every object of the manifest, records them in the
+// inventory and prunes the objects of an earlier revision that are gone.
+// Objects in the inventory of another manifest and existing objects the
+// controller never applied are refused, and fields owned by another manager
+// fail the apply instead of being taken over.
+func (m *ManifestProvider) apply(ctx context.Context, manifest models.Manifest) error {
+	objects, err := m.decode(manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		gvk := object.GroupVersionKind()
+		owner, err := m.otherOwner(manifest.Name, gvk.Group, gvk.Kind, object.GetNamespace(), object.GetName())
+		if err != nil {
+			return err
+		}
+		if owner != "" {
+			return fmt.Errorf("%s %s is managed by manifest %s", gvk.Kind, object.GetName(), owner)
+		}
+
+		resource, err := m.resourceInterface(gvk.Group, gvk.Version, gvk.Kind, object.GetNamespace())
+		if err != nil {
+			return err
+		}
+		current, err := resource.Get(ctx, object.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !appliedByController(current) {
+			return fmt.Errorf("%s %s already exists and is not managed by the controller", gvk.Kind, object.GetName())
+		}
+	}
+
+	applied := map[string]bool{}
+	for _, object := range objects {
+		gvk := object.GroupVersionKind()
+		resource, err := m.resourceInterface(gvk.Group, gvk.Version, gvk.Kind, object.GetNamespace())
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(object.Object)
+		if err != nil {
+			return err
+		}
+		_, err = resource.Patch(ctx, object.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: manifestFieldManager,
+		})
+		if err != nil {
+			return fmt.Errorf("applying %s %s: %w", gvk.Kind, object.GetName(), err)
+		}
+
+		inventoryObject := models.ManifestObject{
+			ModuleReleaseID: manifest.ModuleReleaseID,
+			ManifestName:    manifest.Name,
+			Group:           gvk.Group,
+			Version:         gvk.Version,
+			Kind:            gvk.Kind,
+			Namespace:       object.GetNamespace(),
+			Name:            object.GetName(),
+		}
+		err = m.recordObject(inventoryObject)
+		if err != nil {
+			return err
+		}
+		applied[objectKey(inventoryObject)] = true
+	}
+
+	inventory, err := m.getInventory(manifest.Name)
+	if err != nil {
+		return err
+	}
+	for _, object := range inventory {
+		if applied[objectKey(object)] {
+			continue
+		}
+		err = m.deleteObject(ctx, object)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode splits the manifests into objects, filling in the manifest namespace
+// for namespaced objects that do not set one.
+func (m *ManifestProvider) decode(manifest models.Manifest) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest.Manifests)), 4096)
+	for {
+		object := unstructured.Unstructured{}
+		err := decoder.Decode(&object.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding manifest %s: %w", manifest.Name, err)
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		if object.GetKind() == "" || object.GetName() == "" {
+			return nil, fmt.Errorf("every object of manifest %s needs a kind and a name", manifest.Name)
+		}
+
+		gvk := object.GroupVersionKind()
+		mapping, err := m.restMapping(gvk.Group, gvk.Version, gvk.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if object.GetNamespace() == "" {
+				object.SetNamespace(manifest.Namespace)
+			}
+			if object.GetNamespace() == "" {
+				object.SetNamespace(m.defaultNamespace)
+			}
+			if !m.availableNamespace[object.GetNamespace()] {
+				return nil, fmt.Errorf("unknown namespace %s for %s %s", object.GetNamespace(), gvk.Kind, object.GetName())
+			}
+		} else {
+			if !m.clusterKinds[gvk.GroupKind().String()] {
+				return nil, fmt.Errorf("cluster-scoped kind %s of %s is not allowed", gvk.GroupKind(), object.GetName())
+			}
+			object.SetNamespace("")
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// restMapping resolves the resource of a kind, refreshing the discovery cache
+// once when the kind is unknown, e.g. for a CRD installed by another component.
+func (m *ManifestProvider) restMapping(group string, version string, kind string) (*meta.RESTMapping, error) {
+	groupKind := schema.GroupKind{Group: group, Kind: kind}
+	mapping, err := m.mapper.RESTMapping(groupKind, version)
+	if meta.IsNoMatchError(err) {
+		m.mapper.Reset()
+		mapping, err = m.mapper.RESTMapping(groupKind, version)
+	}
+	return mapping, err
+}
+
+func (m *ManifestProvider) resourceInterface(group string, version string, kind string, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := m.restMapping(group, version, kind)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return m.dynamicClient.Resource(mapping.Resource).Namespace(namespace), nil
+	}
+	return m.dynamicClient.Resource(mapping.Resource), nil
+}
+
+func (m *ManifestProvider) deleteObject(ctx context.Context, object models.ManifestObject) error {
+	resource, err := m.resourceInterface(object.Group, object.Version, object.Kind, object.Namespace)
+	if err != nil {
+		return err
+	}
+	err = resource.Delete(ctx, object.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting %s %s: %w", object.Kind, object.Name, err)
+	}
+
+	result := m.database.Unscoped().Delete(&object)
+	return result.Error
+}
+
+func (m *ManifestProvider) getInventory(manifestName string) ([]models.ManifestObject, error) {
+	var inventory []models.ManifestObject
+	result := m.database.Where("manifest_name = ?", manifestName).Find(&inventory)
+	return inventory, result.Error
+}
+
+func (m *ManifestProvider) recordObject(object models.ManifestObject) error {
+	result := m.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "manifest_name"}, {Name: "api_group"}, {Name: "kind"}, {Name: "namespace"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"module_release_id", "version", "updated_at"}),
+	}).Create(&object)
+	return result.Error
+}
+
+// otherOwner returns the manifest other than manifestName whose inventory
+// holds the object, if any.
+func (m *ManifestProvider) otherOwner(manifestName string, group string, kind string, namespace string, name string) (string, error) {
+	var owners []string
+	result := m.database.Model(&models.ManifestObject{}).
+		Where("api_group = ? AND kind = ? AND namespace = ? AND name = ? AND manifest_name <> ?", group, kind, namespace, name, manifestName).
+		Limit(1).Pluck("manifest_name", &owners)
+	if result.Error != nil || len(owners) == 0 {
+		return "", result.Error
+	}
+	return owners[0], nil
+}
+
+func appliedByController(object *unstructured.Unstructured) bool {
+	for _, entry := range object.GetManagedFields() {
+		if entry.Manager == manifestFieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+func objectKey(object models.ManifestObject) string {
+	return object.Group + "/" + object.Kind + "/" + object.Namespace + "/" + object.Name
+}
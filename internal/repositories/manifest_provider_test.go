@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newTestManifestProvider(clusterKinds ...string) *ManifestProvider {
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	discoveryClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+				{Name: "namespaces", Kind: "Namespace"},
+			},
+		},
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "clusterroles", Kind: "ClusterRole"},
+			},
+		},
+	}
+
+	manifestProvider := &ManifestProvider{}
+	manifestProvider.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	manifestProvider.defaultNamespace = "default"
+	manifestProvider.availableNamespace = map[string]bool{"default": true, "data": true}
+	manifestProvider.clusterKinds = map[string]bool{}
+	for _, kind := range clusterKinds {
+		manifestProvider.clusterKinds[kind] = true
+	}
+	return manifestProvider
+}
+
+func TestDecode(t *testing.T) {
+	type object struct {
+		Kind      string
+		Namespace string
+		Name      string
+	}
+
+	tests := []struct {
+		name         string
+		manifest     models.Manifest
+		clusterKinds []string
+		want         []object
+		wantErr      bool
+	}{
+		{
+			name:     "namespace of the manifest",
+			manifest: models.Manifest{Name: "m", Namespace: "data", Manifests: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"},
+			want:     []object{{Kind: "ConfigMap", Namespace: "data", Name: "settings"}},
+		},
+		{
+			name:     "default namespace",
+			manifest: models.Manifest{Name: "m", Manifests: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"},
+			want:     []object{{Kind: "ConfigMap", Namespace: "default", Name: "settings"}},
+		},
+		{
+			name: "several documents and empty ones",
+			manifest: models.Manifest{Name: "m", Namespace: "data", Manifests: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n---\n" +
+				"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  namespace: default\n"},
+			want: []object{{Kind: "ConfigMap", Namespace: "data", Name: "a"}, {Kind: "ConfigMap", Namespace: "default", Name: "b"}},
+		},
+		{
+			name:     "unavailable namespace",
+			manifest: models.Manifest{Name: "m", Manifests: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: kube-system\n"},
+			wantErr:  true,
+		},
+		{
+			name:     "missing name",
+			manifest: models.Manifest{Name: "m", Manifests: "apiVersion: v1\nkind: ConfigMap\nmetadata: {}\n"},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown kind",
+			manifest: models.Manifest{Name: "m", Manifests: "apiVersion: v1\nkind: Widget\nmetadata:\n  name: w\n"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid yaml",
+			manifest: models.Manifest{Name: "m", Manifests: "kind: [\n"},
+			wantErr:  true,
+		},
+		{
+			name:     "cluster-scoped kind not allowed",
+			manifest: models.Manifest{Name: "m", Manifests: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team\n"},
+			wantErr:  true,
+		},
+		{
+			name:         "allowed cluster-scoped kind drops the namespace",
+			manifest:     models.Manifest{Name: "m", Namespace: "data", Manifests: "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: reader\n  namespace: data\n"},
+			clusterKinds: []string{"ClusterRole.rbac.authorization.k8s.io"},
+			want:         []object{{Kind: "ClusterRole", Name: "reader"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects, err := newTestManifestProvider(test.clusterKinds...).decode(test.manifest)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			var got []object
+			for _, decoded := range objects {
+				got = append(got, object{Kind: decoded.GetKind(), Namespace: decoded.GetNamespace(), Name: decoded.GetName()})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
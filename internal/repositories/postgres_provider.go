@@ -0,0 +1,610 @@
+package repositories
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"github.com/hashicorp/vault/api"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	postgresPasswordLength  = 32
+	postgresPasswordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// postgresManagedComment marks the roles and databases the controller
+	// created, the only ones it drops
+	postgresManagedComment = "managed by warehouse-controller"
+)
+
+// identifiers are interpolated into statements, so only plain names are accepted
+var postgresIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,62}$`)
+
+var postgresPrivileges = map[string]bool{
+	"SELECT":     true,
+	"INSERT":     true,
+	"UPDATE":     true,
+	"DELETE":     true,
+	"TRUNCATE":   true,
+	"REFERENCES": true,
+	"TRIGGER":    true,
+	"ALL":        true,
+}
+
+var postgresConfigColumns = []string{
+	"schemas",
+	"grants",
+	"deletion_policy",
+	"import",
+}
+
+type PostgresProvider struct {
+	database       *gorm.DB
+	vaultClient    *api.Client
+	postgresConfig configs.PostgresConfig
+}
+
+func InitPostgresProvider(db *gorm.DB, vaultClient *api.Client, postgresConfig configs.PostgresConfig) Providers {
+	postgresProvider := &PostgresProvider{}
+	postgresProvider.database = db
+	postgresProvider.vaultClient = vaultClient
+	postgresProvider.postgresConfig = postgresConfig
+
+	return postgresProvider
+}
+
+func (p *PostgresProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.PostgresDatabase{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	if component.Role == "" {
+		component.Role = component.Name
+	}
+	if component.DeletionPolicy == "" {
+		component.DeletionPolicy = models.PostgresDeletionPolicyRetain
+	}
+	return component, nil
+}
+
+func (p *PostgresProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.PostgresDatabase
+	if prevData != nil {
+		oldData, ok = prevData.(models.PostgresDatabase)
+		if !ok {
+			err := errors.New("conversion to postgres database failed")
+			return nil, err
+		}
+	}
+
+	if prevData != nil && processed.Role != oldData.Role {
+		err := errors.New("role of a postgres database cannot be changed")
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (p *PostgresProvider) InstallComponent(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	return p.reconcile(database, nil)
+}
+
+func (p *PostgresProvider) UpdateComponent(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	// the stored database still holds the grants of the previous revision
+	oldDatabaseInterface, err := p.GetDetail(database.Name)
+	if err != nil {
+		return err
+	}
+	oldDatabase := oldDatabaseInterface.(models.PostgresDatabase)
+
+	return p.reconcile(database, oldDatabase.Grants)
+}
+
+// UninstallComponent drops the database, its role and its vault secret when
+// the deletion policy is delete. Retained databases are only forgotten, as
+// are imported databases and roles the controller did not create.
+func (p *PostgresProvider) UninstallComponent(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	if database.DeletionPolicy != models.PostgresDeletionPolicyDelete {
+		return nil
+	}
+	role := postgresRole(database)
+	err := validatePostgres(database)
+	if err != nil {
+		return err
+	}
+
+	admin, closeAdmin, err := p.connect(p.postgresConfig.Database)
+	if err != nil {
+		return err
+	}
+	defer closeAdmin()
+
+	databaseManaged, err := managedBy(admin, "SELECT shobj_description(oid, 'pg_database') FROM pg_database WHERE datname = ?", database.Name)
+	if err != nil {
+		return err
+	}
+	if databaseManaged {
+		err = admin.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteIdentifier(database.Name))).Error
+		if err != nil {
+			return fmt.Errorf("deleting postgres database %s: %w", database.Name, err)
+		}
+	}
+
+	roleManaged, err := managedBy(admin, "SELECT shobj_description(oid, 'pg_authid') FROM pg_roles WHERE rolname = ?", role)
+	if err != nil {
+		return err
+	}
+	if !roleManaged {
+		return nil
+	}
+	err = admin.Exec(fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteIdentifier(role))).Error
+	if err != nil {
+		return fmt.Errorf("deleting postgres role %s: %w", role, err)
+	}
+
+	_, err = p.vaultClient.Logical().Delete(p.vaultPath("metadata", role))
+	return err
+}
+
+func (p *PostgresProvider) GetStatus(databaseInterface interface{}) (responses.ComponentStatus, error) {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: database.Name,
+	}
+	role := postgresRole(database)
+
+	admin, closeAdmin, err := p.connect(p.postgresConfig.Database)
+	if err != nil {
+		return status, err
+	}
+	defer closeAdmin()
+
+	detail := responses.PostgresStatus{
+		Database: database.Name,
+		Role:     role,
+	}
+	detail.DatabaseExists, err = exists(admin, "SELECT count(*) FROM pg_database WHERE datname = ?", database.Name)
+	if err != nil {
+		return status, err
+	}
+	detail.RoleExists, err = exists(admin, "SELECT count(*) FROM pg_roles WHERE rolname = ?", role)
+	if err != nil {
+		return status, err
+	}
+	password, err := p.readPassword(role)
+	if err != nil {
+		return status, err
+	}
+	detail.SecretExists = password != ""
+
+	status.Detail = detail
+	if !detail.DatabaseExists || !detail.RoleExists || !detail.SecretExists {
+		status.Status = "missing"
+		status.Message = "database, role or vault secret is missing"
+		return status, nil
+	}
+	status.Healthy = true
+	status.Status = "available"
+	return status, nil
+}
+
+// GetOutputs exposes the connection details to the other components of the
+// module as "{database}/host", "/port", "/database", "/username" and
+// "/password_secret". The last one is a vault secret reference, so the
+// password itself never ends up in a rendered spec.
+func (p *PostgresProvider) GetOutputs(databaseInterface interface{}) (map[string]string, error) {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return nil, err
+	}
+	role := postgresRole(database)
+
+	outputs := map[string]string{
+		database.Name + "/host":            p.postgresConfig.Host,
+		database.Name + "/port":            strconv.Itoa(p.postgresConfig.Port),
+		database.Name + "/database":        database.Name,
+		database.Name + "/username":        role,
+		database.Name + "/password_secret": p.vaultPath("data", role) + ":password",
+	}
+	return outputs, nil
+}
+
+func (p *PostgresProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := p.database.Model(&models.PostgresDatabase{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (p *PostgresProvider) Add(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	result := p.database.Create(&database)
+	return result.Error
+}
+
+func (p *PostgresProvider) Remove(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	result := p.database.Delete(&models.PostgresDatabase{}, "name = ?", database.Name)
+	return result.Error
+}
+
+func (p *PostgresProvider) Update(databaseInterface interface{}) error {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return err
+	}
+
+	return updateByName(p.database, &database, database.Name, postgresConfigColumns)
+}
+
+func (p *PostgresProvider) GetDetail(releaseName string) (interface{}, error) {
+	var database models.PostgresDatabase
+	result := p.database.Where("name = ?", releaseName).First(&database)
+	return database, result.Error
+}
+
+func (p *PostgresProvider) GetDetailFromComponent(databaseInterface interface{}) (interface{}, error) {
+	database, ok := databaseInterface.(models.PostgresDatabase)
+	if !ok {
+		err := errors.New("conversion to postgres database failed")
+		return nil, err
+	}
+
+	return p.GetDetail(database.Name)
+}
+
+func (p *PostgresProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var databases []models.PostgresDatabase
+	result := p.database.Where("module_release_id = ?", ModuleReleaseID).Find(&databases)
+
+	var databaseInterface []interface{} = make([]interface{}, len(databases))
+	for i, v := range databases {
+		databaseInterface[i] = v
+	}
+
+	return databaseInterface, result.Error
+}
+
+// reconcile creates the role, database and schemas when missing and replaces
+// the grants of the previous revision with the declared ones. Schemas removed
+// from the spec are kept, as dropping them would lose data.
+func (p *PostgresProvider) reconcile(database models.PostgresDatabase, oldGrants models.PostgresGrants) error {
+	err := validatePostgres(database)
+	if err != nil {
+		return err
+	}
+	role := postgresRole(database)
+
+	admin, closeAdmin, err := p.connect(p.postgresConfig.Database)
+	if err != nil {
+		return err
+	}
+	defer closeAdmin()
+
+	err = p.reconcileRole(admin, role, database.Import)
+	if err != nil {
+		return err
+	}
+
+	databaseExists, err := exists(admin, "SELECT count(*) FROM pg_database WHERE datname = ?", database.Name)
+	if err != nil {
+		return err
+	}
+	if databaseExists && !database.Import {
+		managed, err := managedBy(admin, "SELECT shobj_description(oid, 'pg_database') FROM pg_database WHERE datname = ?", database.Name)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			return fmt.Errorf("postgres database %s already exists and was not created by the controller, set import to use it", database.Name)
+		}
+	}
+	if !databaseExists {
+		// CREATE DATABASE cannot run inside a transaction block
+		err = admin.Exec(fmt.Sprintf("CREATE DATABASE %s OWNER %s", quoteIdentifier(database.Name), quoteIdentifier(role))).Error
+		if err != nil {
+			return fmt.Errorf("creating postgres database %s: %w", database.Name, err)
+		}
+		err = admin.Exec(fmt.Sprintf("COMMENT ON DATABASE %s IS '%s'", quoteIdentifier(database.Name), postgresManagedComment)).Error
+		if err != nil {
+			return fmt.Errorf("marking postgres database %s: %w", database.Name, err)
+		}
+	}
+
+	target, closeTarget, err := p.connect(database.Name)
+	if err != nil {
+		return err
+	}
+	defer closeTarget()
+
+	return target.Transaction(func(tx *gorm.DB) error {
+		for _, schema := range database.Schemas {
+			err := tx.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s AUTHORIZATION %s", quoteIdentifier(schema), quoteIdentifier(role))).Error
+			if err != nil {
+				return fmt.Errorf("creating schema %s: %w", schema, err)
+			}
+		}
+
+		for _, grant := range append(oldGrants, database.Grants...) {
+			for _, statement := range revokeStatements(role, grant) {
+				err := tx.Exec(statement).Error
+				if err != nil {
+					return fmt.Errorf("revoking grants of role %s on schema %s: %w", grant.Role, grant.Schema, err)
+				}
+			}
+		}
+		for _, grant := range database.Grants {
+			for _, statement := range grantStatements(database.Name, role, grant) {
+				err := tx.Exec(statement).Error
+				if err != nil {
+					return fmt.Errorf("granting role %s on schema %s: %w", grant.Role, grant.Schema, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// reconcileRole creates the login role with a generated password stored in
+// vault. An existing role whose secret is gone gets a new password, which is
+// refused for roles the controller did not create unless they are imported.
+func (p *PostgresProvider) reconcileRole(admin *gorm.DB, role string, importRole bool) error {
+	roleExists, err := exists(admin, "SELECT count(*) FROM pg_roles WHERE rolname = ?", role)
+	if err != nil {
+		return err
+	}
+	password, err := p.readPassword(role)
+	if err != nil {
+		return err
+	}
+	if roleExists && password != "" {
+		return nil
+	}
+	if roleExists && !importRole {
+		managed, err := managedBy(admin, "SELECT shobj_description(oid, 'pg_authid') FROM pg_roles WHERE rolname = ?", role)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			return fmt.Errorf("postgres role %s already exists and was not created by the controller, set import to reset its password", role)
+		}
+	}
+
+	password, err = generatePassword()
+	if err != nil {
+		return err
+	}
+	// the password is alphanumeric, so it can be inlined as a literal
+	statement := fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD '%s'", quoteIdentifier(role), password)
+	if roleExists {
+		statement = fmt.Sprintf("ALTER ROLE %s WITH LOGIN PASSWORD '%s'", quoteIdentifier(role), password)
+	}
+	err = admin.Exec(statement).Error
+	if err != nil {
+		return fmt.Errorf("creating postgres role %s: %w", role, err)
+	}
+	if !roleExists {
+		err = admin.Exec(fmt.Sprintf("COMMENT ON ROLE %s IS '%s'", quoteIdentifier(role), postgresManagedComment)).Error
+		if err != nil {
+			return fmt.Errorf("marking postgres role %s: %w", role, err)
+		}
+	}
+
+	// the admin needs to be a member of the role to hand ownership to it
+	err = admin.Exec(fmt.Sprintf("GRANT %s TO CURRENT_USER", quoteIdentifier(role))).Error
+	if err != nil {
+		return fmt.Errorf("granting postgres role %s: %w", role, err)
+	}
+
+	_, err = p.vaultClient.Logical().Write(p.vaultPath("data", role), map[string]interface{}{
+		"data": map[string]interface{}{
+			"username": role,
+			"password": password,
+		},
+	})
+	return err
+}
+
+func (p *PostgresProvider) readPassword(role string) (string, error) {
+	secret, err := p.vaultClient.Logical().Read(p.vaultPath("data", role))
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", nil
+	}
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	password, _ := data["password"].(string)
+	return password, nil
+}
+
+// vaultPath returns the kv v2 path of the role secret for the given kind,
+// "data" or "metadata".
+func (p *PostgresProvider) vaultPath(kind string, role string) string {
+	return p.postgresConfig.VaultMount + "/" + kind + "/" + p.postgresConfig.VaultPrefix + "/" + role
+}
+
+func (p *PostgresProvider) connect(databaseName string) (*gorm.DB, func(), error) {
+	if p.postgresConfig.Host == "" {
+		return nil, nil, errors.New("no postgres host configured")
+	}
+	// role passwords are stored in vault
+	if p.vaultClient == nil {
+		return nil, nil, errors.New("no vault configured for postgres passwords")
+	}
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
+		p.postgresConfig.Host,
+		p.postgresConfig.User,
+		p.postgresConfig.Password,
+		databaseName,
+		p.postgresConfig.Port,
+		p.postgresConfig.SSLMode,
+	)
+	// statements carry passwords, so they are never logged
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() { sqlDB.Close() }, nil
+}
+
+func exists(db *gorm.DB, query string, args ...interface{}) (bool, error) {
+	var count int64
+	result := db.Raw(query, args...).Scan(&count)
+	return count > 0, result.Error
+}
+
+// managedBy tells whether the comment selected by query marks an object
+// created by the controller.
+func managedBy(db *gorm.DB, query string, args ...interface{}) (bool, error) {
+	var comment sql.NullString
+	err := db.Raw(query, args...).Row().Scan(&comment)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return comment.Valid && comment.String == postgresManagedComment, nil
+}
+
+func grantStatements(databaseName string, owner string, grant models.PostgresGrant) []string {
+	privileges := strings.ToUpper(strings.Join(grant.Privileges, ", "))
+	return []string{
+		fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", quoteIdentifier(databaseName), quoteIdentifier(grant.Role)),
+		fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", quoteIdentifier(grant.Schema), quoteIdentifier(grant.Role)),
+		fmt.Sprintf("GRANT %s ON ALL TABLES IN SCHEMA %s TO %s", privileges, quoteIdentifier(grant.Schema), quoteIdentifier(grant.Role)),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s GRANT %s ON TABLES TO %s", quoteIdentifier(owner), quoteIdentifier(grant.Schema), privileges, quoteIdentifier(grant.Role)),
+	}
+}
+
+func revokeStatements(owner string, grant models.PostgresGrant) []string {
+	return []string{
+		fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM %s", quoteIdentifier(grant.Schema), quoteIdentifier(grant.Role)),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s REVOKE ALL ON TABLES FROM %s", quoteIdentifier(owner), quoteIdentifier(grant.Schema), quoteIdentifier(grant.Role)),
+		fmt.Sprintf("REVOKE USAGE ON SCHEMA %s FROM %s", quoteIdentifier(grant.Schema), quoteIdentifier(grant.Role)),
+	}
+}
+
+func validatePostgres(database models.PostgresDatabase) error {
+	names := []string{database.Name, postgresRole(database)}
+	names = append(names, database.Schemas...)
+	for _, grant := range database.Grants {
+		names = append(names, grant.Role, grant.Schema)
+		if len(grant.Privileges) == 0 {
+			return fmt.Errorf("grant of role %s on schema %s has no privileges", grant.Role, grant.Schema)
+		}
+		for _, privilege := range grant.Privileges {
+			if !postgresPrivileges[strings.ToUpper(privilege)] {
+				return fmt.Errorf("unknown privilege %s", privilege)
+			}
+		}
+	}
+	for _, name := range names {
+		if !postgresIdentifier.MatchString(name) {
+			return fmt.Errorf("invalid postgres identifier %q", name)
+		}
+	}
+	if database.DeletionPolicy != models.PostgresDeletionPolicyRetain && database.DeletionPolicy != models.PostgresDeletionPolicyDelete {
+		return fmt.Errorf("unknown deletion policy %s", database.DeletionPolicy)
+	}
+	return nil
+}
+
+func postgresRole(database models.PostgresDatabase) string {
+	if database.Role == "" {
+		return database.Name
+	}
+	return database.Role
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + name + `"`
+}
+
+func generatePassword() (string, error) {
+	password := make([]byte, postgresPasswordLength)
+	for i := range password {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(postgresPasswordCharset))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = postgresPasswordCharset[index.Int64()]
+	}
+	return string(password), nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+func TestGrantStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant models.PostgresGrant
+		want  []string
+	}{
+		{
+			name:  "single privilege",
+			grant: models.PostgresGrant{Role: "analyst", Schema: "sales", Privileges: []string{"select"}},
+			want: []string{
+				`GRANT CONNECT ON DATABASE "orders" TO "analyst"`,
+				`GRANT USAGE ON SCHEMA "sales" TO "analyst"`,
+				`GRANT SELECT ON ALL TABLES IN SCHEMA "sales" TO "analyst"`,
+				`ALTER DEFAULT PRIVILEGES FOR ROLE "orders_owner" IN SCHEMA "sales" GRANT SELECT ON TABLES TO "analyst"`,
+			},
+		},
+		{
+			name:  "several privileges",
+			grant: models.PostgresGrant{Role: "loader", Schema: "public", Privileges: []string{"select", "Insert", "UPDATE"}},
+			want: []string{
+				`GRANT CONNECT ON DATABASE "orders" TO "loader"`,
+				`GRANT USAGE ON SCHEMA "public" TO "loader"`,
+				`GRANT SELECT, INSERT, UPDATE ON ALL TABLES IN SCHEMA "public" TO "loader"`,
+				`ALTER DEFAULT PRIVILEGES FOR ROLE "orders_owner" IN SCHEMA "public" GRANT SELECT, INSERT, UPDATE ON TABLES TO "loader"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := grantStatements("orders", "orders_owner", test.grant)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidatePostgres(t *testing.T) {
+	valid := func() models.PostgresDatabase {
+		return models.PostgresDatabase{
+			Name:           "orders",
+			Schemas:        models.StringList{"sales"},
+			Grants:         models.PostgresGrants{{Role: "analyst", Schema: "sales", Privileges: []string{"select"}}},
+			DeletionPolicy: models.PostgresDeletionPolicyRetain,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		change  func(*models.PostgresDatabase)
+		wantErr bool
+	}{
+		{name: "valid", change: func(*models.PostgresDatabase) {}},
+		{name: "longest name", change: func(d *models.PostgresDatabase) { d.Name = strings.Repeat("o", 63) }},
+		{name: "role", change: func(d *models.PostgresDatabase) { d.Role = "orders_owner" }},
+		{name: "delete policy", change: func(d *models.PostgresDatabase) { d.DeletionPolicy = models.PostgresDeletionPolicyDelete }},
+		{name: "quote in name", change: func(d *models.PostgresDatabase) { d.Name = `orders"; DROP DATABASE x; --` }, wantErr: true},
+		{name: "name starting with a digit", change: func(d *models.PostgresDatabase) { d.Name = "1orders" }, wantErr: true},
+		{name: "name too long", change: func(d *models.PostgresDatabase) { d.Name = strings.Repeat("o", 64) }, wantErr: true},
+		{name: "invalid role", change: func(d *models.PostgresDatabase) { d.Role = "orders-owner" }, wantErr: true},
+		{name: "invalid schema", change: func(d *models.PostgresDatabase) { d.Schemas = models.StringList{"sales.eu"} }, wantErr: true},
+		{name: "invalid grant role", change: func(d *models.PostgresDatabase) { d.Grants[0].Role = "" }, wantErr: true},
+		{name: "grant without privileges", change: func(d *models.PostgresDatabase) { d.Grants[0].Privileges = nil }, wantErr: true},
+		{name: "unknown privilege", change: func(d *models.PostgresDatabase) { d.Grants[0].Privileges = []string{"superuser"} }, wantErr: true},
+		{name: "unknown deletion policy", change: func(d *models.PostgresDatabase) { d.DeletionPolicy = "force" }, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := valid()
+			test.change(&database)
+			err := validatePostgres(database)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
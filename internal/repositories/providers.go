@@ -1,5 +1,37 @@
 package repositories
 
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+// ErrComponentExists is matched by the errors InstallComponent returns for a
+// component that already existed outside the controller. It is not the
+// release's to remove, so a failed release leaves it in place.
+var ErrComponentExists = errors.New("component already exists")
+
+// componentExistsError marks an error as ErrComponentExists, keeping its
+// message and the errors it wraps.
+type componentExistsError struct {
+	error
+}
+
+func (e componentExistsError) Unwrap() error {
+	return e.error
+}
+
+func (e componentExistsError) Is(target error) bool {
+	return target == ErrComponentExists
+}
+
+// existingComponent marks err as the failure of a component that already
+// existed.
+func existingComponent(err error) error {
+	return componentExistsError{err}
+}
+
 type Providers interface {
 	Convert(interface{}) (interface{}, error)
 
@@ -8,6 +40,7 @@ type Providers interface {
 	InstallComponent(interface{}) error
 	UpdateComponent(interface{}) error
 	UninstallComponent(interface{}) error
+	GetStatus(interface{}) (responses.ComponentStatus, error)
 
 	GetAllName() ([]string, error)
 	Add(interface{}) error
@@ -17,3 +50,28 @@ type Providers interface {
 	GetDetailFromComponent(interface{}) (interface{}, error)
 	GetFromModuleReleaseID(uint) ([]interface{}, error)
 }
+
+// OutputProviders are providers whose components produce values, such as
+// generated ARNs, that other components of the same module can refer to.
+type OutputProviders interface {
+	GetOutputs(interface{}) (map[string]string, error)
+}
+
+// updateByName saves a component over the recorded one with the same name.
+func updateByName(db *gorm.DB, component interface{}, name string, configColumns []string) error {
+	return updateWhere(db, component, map[string]interface{}{"name": name}, configColumns)
+}
+
+// updateWhere saves a component over the recorded one matching every column
+// of conditions. Updates skips zero values, so the config columns, which hold
+// the desired state of the component, are written again to clear the ones
+// left out.
+func updateWhere(db *gorm.DB, component interface{}, conditions map[string]interface{}, configColumns []string) error {
+	result := db.Model(component).Where(conditions).Updates(component)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = db.Model(component).Where(conditions).Select(configColumns).Updates(component)
+	return result.Error
+}
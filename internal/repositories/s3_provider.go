@@ -0,0 +1,491 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+// DeleteObjects accepts at most 1000 keys per call
+const s3DeleteBatchSize = 1000
+
+var s3ConfigColumns = []string{
+	"versioning",
+	"encryption_key_id",
+	"block_public_access",
+	"lifecycle_rules",
+	"tags",
+	"deletion_policy",
+	"import",
+}
+
+type S3Provider struct {
+	database  *gorm.DB
+	awsConfig configs.AWSConfig
+}
+
+func InitS3Provider(db *gorm.DB, awsConfig configs.AWSConfig) Providers {
+	s3Provider := &S3Provider{}
+	s3Provider.database = db
+	s3Provider.awsConfig = awsConfig
+
+	return s3Provider
+}
+
+func (s *S3Provider) s3Client(account string, region string) (*s3.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// local stand-ins serve buckets by path rather than by host name
+		o.UsePathStyle = s.awsConfig.Endpoint != ""
+	}), nil
+}
+
+func (s *S3Provider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.S3Bucket{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	if component.DeletionPolicy == "" {
+		component.DeletionPolicy = models.S3DeletionPolicyRetain
+	}
+	return component, nil
+}
+
+func (s *S3Provider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.S3Bucket
+	if prevData != nil {
+		oldData, ok = prevData.(models.S3Bucket)
+		if !ok {
+			err := errors.New("conversion to s3 bucket failed")
+			return nil, err
+		}
+	}
+
+	if prevData != nil && (processed.Account != oldData.Account || processed.Region != oldData.Region) {
+		err := errors.New("account and region of an s3 bucket cannot be changed")
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (s *S3Provider) InstallComponent(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	client, err := s.s3Client(bucket.Account, bucket.Region)
+	if err != nil {
+		return err
+	}
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, bucket.Account, bucket.Region)
+	if err != nil {
+		return err
+	}
+	region := cfg.Region
+
+	input := s3.CreateBucketInput{
+		Bucket: &bucket.Name,
+	}
+	// us-east-1 is the default location and is rejected as constraint
+	if region != "" && region != "us-east-1" {
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		}
+	}
+	_, err = client.CreateBucket(context.TODO(), &input)
+	var ownedByYou *types.BucketAlreadyOwnedByYou
+	var alreadyExists *types.BucketAlreadyExists
+	if errors.As(err, &alreadyExists) {
+		return existingComponent(fmt.Errorf("bucket %s is owned by another account", bucket.Name))
+	}
+	existing := errors.As(err, &ownedByYou)
+	if existing && !bucket.Import {
+		return existingComponent(fmt.Errorf("bucket %s already exists, set import to manage it", bucket.Name))
+	}
+	if err != nil && !existing {
+		return err
+	}
+
+	err = s.applyBucketConfig(context.TODO(), client, bucket)
+	if err != nil && existing {
+		// the imported bucket stays whatever happens to the release
+		return existingComponent(err)
+	}
+	return err
+}
+
+func (s *S3Provider) UpdateComponent(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	client, err := s.s3Client(bucket.Account, bucket.Region)
+	if err != nil {
+		return err
+	}
+
+	return s.applyBucketConfig(context.TODO(), client, bucket)
+}
+
+// UninstallComponent follows the deletion policy of the bucket. Retained
+// buckets are only forgotten, delete-if-empty refuses to delete a bucket that
+// still holds objects and force empties the bucket, every version included.
+func (s *S3Provider) UninstallComponent(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	switch bucket.DeletionPolicy {
+	case "", models.S3DeletionPolicyRetain:
+		return nil
+	case models.S3DeletionPolicyDeleteIfEmpty, models.S3DeletionPolicyForce:
+	default:
+		return fmt.Errorf("unknown deletion policy %s", bucket.DeletionPolicy)
+	}
+
+	client, err := s.s3Client(bucket.Account, bucket.Region)
+	if err != nil {
+		return err
+	}
+
+	if bucket.DeletionPolicy == models.S3DeletionPolicyForce {
+		err = s.emptyBucket(context.TODO(), client, bucket.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		empty, err := s.isBucketEmpty(context.TODO(), client, bucket.Name)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return fmt.Errorf("s3 bucket %s is not empty and its deletion policy is %s", bucket.Name, bucket.DeletionPolicy)
+		}
+	}
+
+	input := s3.DeleteBucketInput{
+		Bucket: &bucket.Name,
+	}
+	_, err = client.DeleteBucket(context.TODO(), &input)
+	return err
+}
+
+func (s *S3Provider) GetStatus(bucketInterface interface{}) (responses.ComponentStatus, error) {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: bucket.Name,
+	}
+
+	client, err := s.s3Client(bucket.Account, bucket.Region)
+	if err != nil {
+		return status, err
+	}
+
+	input := s3.HeadBucketInput{
+		Bucket: &bucket.Name,
+	}
+	_, err = client.HeadBucket(context.TODO(), &input)
+	if err != nil {
+		status.Status = "unavailable"
+		status.Message = err.Error()
+		return status, nil
+	}
+
+	status.Healthy = true
+	status.Status = "available"
+	return status, nil
+}
+
+func (s *S3Provider) GetAllName() ([]string, error) {
+	var names []string
+	result := s.database.Model(&models.S3Bucket{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (s *S3Provider) Add(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	result := s.database.Create(&bucket)
+	return result.Error
+}
+
+func (s *S3Provider) Remove(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	result := s.database.Delete(&models.S3Bucket{}, "name = ?", bucket.Name)
+	return result.Error
+}
+
+func (s *S3Provider) Update(bucketInterface interface{}) error {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return err
+	}
+
+	return updateByName(s.database, &bucket, bucket.Name, s3ConfigColumns)
+}
+
+func (s *S3Provider) GetDetail(releaseName string) (interface{}, error) {
+	var bucket models.S3Bucket
+	result := s.database.Where("name = ?", releaseName).First(&bucket)
+	return bucket, result.Error
+}
+
+func (s *S3Provider) GetDetailFromComponent(bucketInterface interface{}) (interface{}, error) {
+	bucket, ok := bucketInterface.(models.S3Bucket)
+	if !ok {
+		err := errors.New("conversion to s3 bucket failed")
+		return nil, err
+	}
+
+	return s.GetDetail(bucket.Name)
+}
+
+func (s *S3Provider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var buckets []models.S3Bucket
+	result := s.database.Where("module_release_id = ?", ModuleReleaseID).Find(&buckets)
+
+	var bucketInterface []interface{} = make([]interface{}, len(buckets))
+	for i, v := range buckets {
+		bucketInterface[i] = v
+	}
+
+	return bucketInterface, result.Error
+}
+
+// applyBucketConfig puts the versioning, encryption, public access block,
+// lifecycle rules and tags of the bucket, removing the ones no longer set.
+// Imported buckets keep the settings their spec leaves out: only what the
+// spec sets is put, and nothing is removed or turned off.
+func (s *S3Provider) applyBucketConfig(ctx context.Context, client *s3.Client, bucket models.S3Bucket) error {
+	owned := !bucket.Import
+
+	if bucket.Versioning || owned {
+		versioning := types.BucketVersioningStatusSuspended
+		if bucket.Versioning {
+			versioning = types.BucketVersioningStatusEnabled
+		}
+		_, err := client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+			Bucket:                  &bucket.Name,
+			VersioningConfiguration: &types.VersioningConfiguration{Status: versioning},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if bucket.EncryptionKeyID != "" || owned {
+		encryption := &types.ServerSideEncryptionByDefault{
+			SSEAlgorithm: types.ServerSideEncryptionAes256,
+		}
+		if bucket.EncryptionKeyID != "" {
+			encryption.SSEAlgorithm = types.ServerSideEncryptionAwsKms
+			encryption.KMSMasterKeyID = &bucket.EncryptionKeyID
+		}
+		_, err := client.PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+			Bucket: &bucket.Name,
+			ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+				Rules: []types.ServerSideEncryptionRule{{
+					ApplyServerSideEncryptionByDefault: encryption,
+					BucketKeyEnabled:                   bucket.EncryptionKeyID != "",
+				}},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// public access is blocked unless explicitly disabled
+	var err error
+	if bucket.BlockPublicAccess != nil && !*bucket.BlockPublicAccess {
+		_, err = client.DeletePublicAccessBlock(ctx, &s3.DeletePublicAccessBlockInput{
+			Bucket: &bucket.Name,
+		})
+	} else if bucket.BlockPublicAccess != nil || owned {
+		_, err = client.PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+			Bucket: &bucket.Name,
+			PublicAccessBlockConfiguration: &types.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       true,
+				BlockPublicPolicy:     true,
+				IgnorePublicAcls:      true,
+				RestrictPublicBuckets: true,
+			},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(bucket.LifecycleRules) > 0 {
+		_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket: &bucket.Name,
+			LifecycleConfiguration: &types.BucketLifecycleConfiguration{
+				Rules: lifecycleRules(bucket.LifecycleRules),
+			},
+		})
+	} else if owned {
+		_, err = client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket: &bucket.Name,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(bucket.Tags) > 0 {
+		tagSet := []types.Tag{}
+		for key, value := range bucket.Tags {
+			key, value := key, value
+			tagSet = append(tagSet, types.Tag{Key: &key, Value: &value})
+		}
+		_, err = client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+			Bucket:  &bucket.Name,
+			Tagging: &types.Tagging{TagSet: tagSet},
+		})
+	} else if owned {
+		_, err = client.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{
+			Bucket: &bucket.Name,
+		})
+	}
+	return err
+}
+
+func lifecycleRules(rules models.S3LifecycleRules) []types.LifecycleRule {
+	result := make([]types.LifecycleRule, len(rules))
+	for i, rule := range rules {
+		rule := rule
+		lifecycleRule := types.LifecycleRule{
+			ID:     &rule.ID,
+			Status: types.ExpirationStatusEnabled,
+			Filter: &types.LifecycleRuleFilterMemberPrefix{Value: rule.Prefix},
+		}
+		if rule.ExpirationDays > 0 {
+			lifecycleRule.Expiration = &types.LifecycleExpiration{Days: rule.ExpirationDays}
+		}
+		if rule.NoncurrentExpirationDays > 0 {
+			lifecycleRule.NoncurrentVersionExpiration = &types.NoncurrentVersionExpiration{NoncurrentDays: rule.NoncurrentExpirationDays}
+		}
+		if rule.AbortIncompleteUploadDays > 0 {
+			lifecycleRule.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{DaysAfterInitiation: rule.AbortIncompleteUploadDays}
+		}
+		for _, transition := range rule.Transitions {
+			lifecycleRule.Transitions = append(lifecycleRule.Transitions, types.Transition{
+				Days:         transition.Days,
+				StorageClass: types.TransitionStorageClass(transition.StorageClass),
+			})
+		}
+		result[i] = lifecycleRule
+	}
+	return result
+}
+
+func (s *S3Provider) isBucketEmpty(ctx context.Context, client *s3.Client, name string) (bool, error) {
+	output, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+		Bucket:  &name,
+		MaxKeys: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(output.Versions) == 0 && len(output.DeleteMarkers) == 0, nil
+}
+
+// emptyBucket deletes every object version and delete marker of the bucket.
+func (s *S3Provider) emptyBucket(ctx context.Context, client *s3.Client, name string) error {
+	input := s3.ListObjectVersionsInput{
+		Bucket:  &name,
+		MaxKeys: s3DeleteBatchSize,
+	}
+	for {
+		output, err := client.ListObjectVersions(ctx, &input)
+		if err != nil {
+			return err
+		}
+
+		objects := []types.ObjectIdentifier{}
+		for _, version := range output.Versions {
+			objects = append(objects, types.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range output.DeleteMarkers {
+			objects = append(objects, types.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+
+		for start := 0; start < len(objects); start += s3DeleteBatchSize {
+			end := start + s3DeleteBatchSize
+			if end > len(objects) {
+				end = len(objects)
+			}
+			deleteOutput, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: &name,
+				Delete: &types.Delete{Objects: objects[start:end], Quiet: true},
+			})
+			if err != nil {
+				return err
+			}
+			if len(deleteOutput.Errors) > 0 {
+				failed := deleteOutput.Errors[0]
+				return fmt.Errorf("deleting %s from s3 bucket %s: %s", *failed.Key, name, *failed.Message)
+			}
+		}
+
+		if !output.IsTruncated {
+			return nil
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
+	}
+}
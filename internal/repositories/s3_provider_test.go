@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+// recordingHTTPClient answers every request with an empty success and
+// records it as the method and the bucket subresource, e.g. "PUT tagging".
+type recordingHTTPClient struct {
+	calls []string
+}
+
+func (r *recordingHTTPClient) Do(request *http.Request) (*http.Response, error) {
+	subresource := ""
+	for key := range request.URL.Query() {
+		if key != "x-id" {
+			subresource = key
+		}
+	}
+	r.calls = append(r.calls, request.Method+" "+subresource)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    request,
+	}, nil
+}
+
+func TestApplyBucketConfig(t *testing.T) {
+	disabled := false
+	enabled := true
+
+	tests := []struct {
+		name   string
+		bucket models.S3Bucket
+		want   []string
+	}{
+		{
+			name:   "created bucket without settings",
+			bucket: models.S3Bucket{Name: "raw"},
+			want:   []string{"PUT versioning", "PUT encryption", "PUT publicAccessBlock", "DELETE lifecycle", "DELETE tagging"},
+		},
+		{
+			name: "created bucket with every setting",
+			bucket: models.S3Bucket{
+				Name:              "raw",
+				Versioning:        true,
+				EncryptionKeyID:   "key",
+				BlockPublicAccess: &disabled,
+				LifecycleRules:    models.S3LifecycleRules{{ID: "expire", ExpirationDays: 30}},
+				Tags:              models.StringMap{"team": "data"},
+			},
+			want: []string{"PUT versioning", "PUT encryption", "DELETE publicAccessBlock", "PUT lifecycle", "PUT tagging"},
+		},
+		{
+			name:   "imported bucket without settings",
+			bucket: models.S3Bucket{Name: "raw", Import: true},
+		},
+		{
+			name: "imported bucket with some settings",
+			bucket: models.S3Bucket{
+				Name:              "raw",
+				Import:            true,
+				BlockPublicAccess: &enabled,
+				Tags:              models.StringMap{"team": "data"},
+			},
+			want: []string{"PUT publicAccessBlock", "PUT tagging"},
+		},
+		{
+			name: "imported bucket opening public access",
+			bucket: models.S3Bucket{
+				Name:              "raw",
+				Import:            true,
+				Versioning:        true,
+				EncryptionKeyID:   "key",
+				BlockPublicAccess: &disabled,
+			},
+			want: []string{"PUT versioning", "PUT encryption", "DELETE publicAccessBlock"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &recordingHTTPClient{}
+			client := s3.New(s3.Options{
+				Region:           "us-east-1",
+				Credentials:      aws.AnonymousCredentials{},
+				EndpointResolver: s3.EndpointResolverFromURL("http://localhost"),
+				UsePathStyle:     true,
+				HTTPClient:       recorder,
+			})
+
+			err := (&S3Provider{}).applyBucketConfig(context.TODO(), client, test.bucket)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(recorder.calls, test.want) {
+				t.Errorf("got %v, want %v", recorder.calls, test.want)
+			}
+		})
+	}
+}
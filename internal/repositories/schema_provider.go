@@ -0,0 +1,357 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/schemaregistry"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var schemaConfigColumns = []string{
+	"schema_type",
+	"schema",
+	"compatibility",
+}
+
+type SchemaProvider struct {
+	database       *gorm.DB
+	registryClient *schemaregistry.Client
+}
+
+func InitSchemaProvider(db *gorm.DB, registryClient *schemaregistry.Client) Providers {
+	schemaProvider := &SchemaProvider{}
+	schemaProvider.database = db
+	schemaProvider.registryClient = registryClient
+
+	return schemaProvider
+}
+
+func (s *SchemaProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Schema{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (s *SchemaProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Schema
+	if prevData != nil {
+		oldData, ok = prevData.(models.Schema)
+		if !ok {
+			err := errors.New("conversion to schema failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+// InstallComponent checks the schema against the latest registered version
+// under the desired compatibility level and registers it. The registry only
+// checks against the level of the subject, so a changed level is set for the
+// check and put back when the schema is incompatible. Without a level the
+// subject keeps the one it has.
+func (s *SchemaProvider) InstallComponent(schemaInterface interface{}) error {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return err
+	}
+
+	registrySchema, err := toRegistrySchema(schema)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.registryClient.GetSubjectCompatibility(context.TODO(), schema.Name)
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return err
+	}
+	desired := strings.ToUpper(schema.Compatibility)
+	changed := desired != "" && desired != previous
+	if changed {
+		err = s.registryClient.SetCompatibility(context.TODO(), schema.Name, desired)
+		if err != nil {
+			return err
+		}
+	}
+
+	compatible, messages, err := s.registryClient.CheckCompatibility(context.TODO(), schema.Name, registrySchema)
+	// the check only finds the subject when it already has versions
+	existed := err == nil
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return s.restoreCompatibility(schema.Name, previous, changed, err)
+	}
+	if err == nil && !compatible {
+		err = fmt.Errorf("schema is incompatible with the latest version of subject %s: %s", schema.Name, strings.Join(messages, "; "))
+		return s.restoreCompatibility(schema.Name, previous, changed, err)
+	}
+
+	_, err = s.registryClient.Register(context.TODO(), schema.Name, registrySchema)
+	if err != nil {
+		return err
+	}
+	registered, err := s.registryClient.Lookup(context.TODO(), schema.Name, registrySchema)
+	if err != nil {
+		return err
+	}
+
+	return s.recordSchema(schema.Name, registered.ID, registered.Version, existed)
+}
+
+// restoreCompatibility puts back the level the subject had before a failed
+// check and returns the failure.
+func (s *SchemaProvider) restoreCompatibility(subject string, previous string, changed bool, failure error) error {
+	if !changed {
+		return failure
+	}
+	var err error
+	if previous != "" {
+		err = s.registryClient.SetCompatibility(context.TODO(), subject, previous)
+	} else {
+		err = s.registryClient.DeleteCompatibility(context.TODO(), subject)
+	}
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return fmt.Errorf("%w, restoring the compatibility level: %s", failure, err)
+	}
+	return failure
+}
+
+func (s *SchemaProvider) UpdateComponent(schemaInterface interface{}) error {
+	return s.InstallComponent(schemaInterface)
+}
+
+func (s *SchemaProvider) UninstallComponent(schemaInterface interface{}) error {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return err
+	}
+
+	// subjects the controller did not create, including the ones whose first
+	// registration failed, are left to their owners
+	var registered models.RegisteredSchema
+	result := s.database.Where("subject = ?", schema.Name).First(&registered)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) || registered.Adopted {
+		return nil
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	err := s.registryClient.DeleteSubject(context.TODO(), schema.Name)
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return err
+	}
+	err = s.registryClient.DeleteCompatibility(context.TODO(), schema.Name)
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *SchemaProvider) GetStatus(schemaInterface interface{}) (responses.ComponentStatus, error) {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: schema.Name,
+	}
+
+	latest, err := s.registryClient.GetLatest(context.TODO(), schema.Name)
+	if errors.Is(err, schemaregistry.ErrNotFound) {
+		status.Status = "missing"
+		status.Message = "subject is not registered"
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	compatibility, err := s.registryClient.GetCompatibility(context.TODO(), schema.Name)
+	if err != nil && !errors.Is(err, schemaregistry.ErrNotFound) {
+		return status, err
+	}
+
+	status.Detail = responses.SchemaStatus{
+		Subject:       schema.Name,
+		SchemaID:      latest.ID,
+		Version:       latest.Version,
+		Compatibility: compatibility,
+	}
+
+	var registered models.RegisteredSchema
+	result := s.database.Where("subject = ?", schema.Name).First(&registered)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return status, result.Error
+	}
+	if registered.SchemaID != latest.ID {
+		status.Status = "outdated"
+		status.Message = fmt.Sprintf("latest version %d was not registered by the controller", latest.Version)
+		return status, nil
+	}
+	status.Healthy = true
+	status.Status = "registered"
+	return status, nil
+}
+
+// GetOutputs exposes the id and version of the registered schema to the other
+// components of the module as "{subject}/id" and "{subject}/version".
+func (s *SchemaProvider) GetOutputs(schemaInterface interface{}) (map[string]string, error) {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return nil, err
+	}
+
+	var registered models.RegisteredSchema
+	result := s.database.Where("subject = ?", schema.Name).First(&registered)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	outputs := map[string]string{
+		schema.Name + "/id":      strconv.Itoa(registered.SchemaID),
+		schema.Name + "/version": strconv.Itoa(registered.Version),
+	}
+	return outputs, nil
+}
+
+func (s *SchemaProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := s.database.Model(&models.Schema{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (s *SchemaProvider) Add(schemaInterface interface{}) error {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return err
+	}
+
+	result := s.database.Create(&schema)
+	return result.Error
+}
+
+func (s *SchemaProvider) Remove(schemaInterface interface{}) error {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return err
+	}
+
+	result := s.database.Unscoped().Delete(&models.RegisteredSchema{}, "subject = ?", schema.Name)
+	if result.Error != nil {
+		return result.Error
+	}
+	result = s.database.Delete(&models.Schema{}, "name = ?", schema.Name)
+	return result.Error
+}
+
+func (s *SchemaProvider) Update(schemaInterface interface{}) error {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return err
+	}
+
+	return updateByName(s.database, &schema, schema.Name, schemaConfigColumns)
+}
+
+func (s *SchemaProvider) GetDetail(releaseName string) (interface{}, error) {
+	var schema models.Schema
+	result := s.database.Where("name = ?", releaseName).First(&schema)
+	return schema, result.Error
+}
+
+func (s *SchemaProvider) GetDetailFromComponent(schemaInterface interface{}) (interface{}, error) {
+	schema, ok := schemaInterface.(models.Schema)
+	if !ok {
+		err := errors.New("conversion to schema failed")
+		return nil, err
+	}
+
+	return s.GetDetail(schema.Name)
+}
+
+func (s *SchemaProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var schemas []models.Schema
+	result := s.database.Where("module_release_id = ?", ModuleReleaseID).Find(&schemas)
+
+	var schemaInterface []interface{} = make([]interface{}, len(schemas))
+	for i, v := range schemas {
+		schemaInterface[i] = v
+	}
+
+	return schemaInterface, result.Error
+}
+
+// recordSchema saves the latest registration of the subject. Whether the
+// subject was adopted is only decided by its first registration.
+func (s *SchemaProvider) recordSchema(subject string, schemaID int, version int, adopted bool) error {
+	registered := models.RegisteredSchema{
+		Subject:  subject,
+		SchemaID: schemaID,
+		Version:  version,
+		Adopted:  adopted,
+	}
+	result := s.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schema_id", "version", "updated_at"}),
+	}).Create(&registered)
+	return result.Error
+}
+
+func toRegistrySchema(schema models.Schema) (schemaregistry.Schema, error) {
+	if schema.Schema == "" {
+		return schemaregistry.Schema{}, fmt.Errorf("subject %s has no schema", schema.Name)
+	}
+
+	schemaType := strings.ToUpper(schema.SchemaType)
+	switch schemaType {
+	case "", models.SchemaTypeAvro:
+		// the registry treats a missing type as avro
+		schemaType = ""
+	case models.SchemaTypeJSON, models.SchemaTypeProtobuf:
+	default:
+		return schemaregistry.Schema{}, fmt.Errorf("unknown schema type %s", schema.SchemaType)
+	}
+
+	registrySchema := schemaregistry.Schema{
+		SchemaType: schemaType,
+		Schema:     schema.Schema,
+	}
+	return registrySchema, nil
+}
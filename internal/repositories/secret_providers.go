@@ -1,5 +1,84 @@
 package repositories
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
 type SecretProviders interface {
 	GetSecret(string) (string, error)
 }
+
+// resolveSecret reads the secret a reference points to from its provider.
+func resolveSecret(secretProviders map[string]SecretProviders, secret models.SecretReference) (string, error) {
+	secretProvider, ok := secretProviders[secret.Provider]
+	if !ok {
+		return "", fmt.Errorf("secret provider %s is not configured", secret.Provider)
+	}
+	return secretProvider.GetSecret(secret.Key)
+}
+
+// awsSecretKey is a key of the AWS secret providers, written like the secrets
+// of ECS task definitions: id[:json-key[:version]]. The id is a name or an
+// ARN, in which case the region of the ARN is used.
+type awsSecretKey struct {
+	ID      string
+	Region  string
+	JSONKey string
+	Version string
+}
+
+// parseAWSSecretKey splits a key whose ARNs have arnParts colon separated
+// parts. Names cannot contain colons, so the remaining parts are the JSON key
+// and the version.
+func parseAWSSecretKey(key string, arnParts int) (awsSecretKey, error) {
+	parts := strings.Split(key, ":")
+	parsed := awsSecretKey{}
+	if parts[0] == "arn" {
+		if len(parts) < arnParts {
+			return parsed, fmt.Errorf("key %s parsing error", key)
+		}
+		parsed.ID = strings.Join(parts[:arnParts], ":")
+		parsed.Region = parts[3]
+		parts = parts[arnParts:]
+	} else {
+		parsed.ID = parts[0]
+		parts = parts[1:]
+	}
+	if parsed.ID == "" || len(parts) > 2 {
+		return parsed, fmt.Errorf("key %s parsing error", key)
+	}
+	if len(parts) > 0 {
+		parsed.JSONKey = parts[0]
+	}
+	if len(parts) > 1 {
+		parsed.Version = parts[1]
+	}
+	return parsed, nil
+}
+
+// jsonSecretValue returns the value of jsonKey in a secret holding a JSON
+// object, or the whole secret when no key is given. Values that are not
+// strings are returned as JSON.
+func jsonSecretValue(secret string, jsonKey string) (string, error) {
+	if jsonKey == "" {
+		return secret, nil
+	}
+	data := map[string]interface{}{}
+	err := json.Unmarshal([]byte(secret), &data)
+	if err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	value, ok := data[jsonKey]
+	if !ok {
+		return "", fmt.Errorf("key %s does not exist", jsonKey)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import "testing"
+
+func TestParseAWSSecretKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		arnParts int
+		want     awsSecretKey
+		wantErr  bool
+	}{
+		{name: "name", key: "warehouse/api", arnParts: secretsManagerARNParts, want: awsSecretKey{ID: "warehouse/api"}},
+		{name: "name and json key", key: "warehouse/api:key", arnParts: secretsManagerARNParts, want: awsSecretKey{ID: "warehouse/api", JSONKey: "key"}},
+		{name: "name, json key and version", key: "warehouse/api:key:AWSPREVIOUS", arnParts: secretsManagerARNParts, want: awsSecretKey{ID: "warehouse/api", JSONKey: "key", Version: "AWSPREVIOUS"}},
+		{name: "version without json key", key: "warehouse/api::AWSPREVIOUS", arnParts: secretsManagerARNParts, want: awsSecretKey{ID: "warehouse/api", Version: "AWSPREVIOUS"}},
+		{
+			name:     "secrets manager arn",
+			key:      "arn:aws:secretsmanager:ap-southeast-1:123456789012:secret:warehouse/api-AbCdEf:key",
+			arnParts: secretsManagerARNParts,
+			want:     awsSecretKey{ID: "arn:aws:secretsmanager:ap-southeast-1:123456789012:secret:warehouse/api-AbCdEf", Region: "ap-southeast-1", JSONKey: "key"},
+		},
+		{
+			name:     "ssm arn",
+			key:      "arn:aws:ssm:us-east-1:123456789012:parameter/warehouse/endpoint::3",
+			arnParts: ssmARNParts,
+			want:     awsSecretKey{ID: "arn:aws:ssm:us-east-1:123456789012:parameter/warehouse/endpoint", Region: "us-east-1", Version: "3"},
+		},
+		{name: "short arn", key: "arn:aws:ssm:us-east-1", arnParts: ssmARNParts, wantErr: true},
+		{name: "empty", key: "", arnParts: ssmARNParts, wantErr: true},
+		{name: "empty name", key: ":key", arnParts: ssmARNParts, wantErr: true},
+		{name: "too many parts", key: "warehouse/api:key:AWSCURRENT:extra", arnParts: secretsManagerARNParts, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseAWSSecretKey(test.key, test.arnParts)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJSONSecretValue(t *testing.T) {
+	secret := `{"username": "warehouse", "port": 5432, "options": {"ssl": true}}`
+
+	tests := []struct {
+		name    string
+		secret  string
+		jsonKey string
+		want    string
+		wantErr bool
+	}{
+		{name: "whole secret", secret: "plain", want: "plain"},
+		{name: "string", secret: secret, jsonKey: "username", want: "warehouse"},
+		{name: "number", secret: secret, jsonKey: "port", want: "5432"},
+		{name: "object", secret: secret, jsonKey: "options", want: `{"ssl":true}`},
+		{name: "missing key", secret: secret, jsonKey: "password", wantErr: true},
+		{name: "not an object", secret: "plain", jsonKey: "username", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := jsonSecretValue(test.secret, test.jsonKey)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
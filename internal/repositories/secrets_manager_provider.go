@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+// secretsManagerARNParts is the number of parts of
+// arn:aws:secretsmanager:region:account:secret:name.
+const secretsManagerARNParts = 7
+
+type SecretsManagerSecretProvider struct {
+	awsConfig            configs.AWSConfig
+	secretsManagerConfig configs.SecretsManagerConfig
+}
+
+func InitSecretsManagerSecretProvider(awsConfig configs.AWSConfig, secretsManagerConfig configs.SecretsManagerConfig) SecretProviders {
+	secretsManagerSecretProvider := &SecretsManagerSecretProvider{}
+	secretsManagerSecretProvider.awsConfig = awsConfig
+	secretsManagerSecretProvider.secretsManagerConfig = secretsManagerConfig
+	return secretsManagerSecretProvider
+}
+
+func (s *SecretsManagerSecretProvider) secretsManagerClient(account string, region string) (*secretsmanager.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return secretsmanager.NewFromConfig(cfg), nil
+}
+
+// GetSecret reads secret-id[:json-key[:version-stage]], the version stage
+// defaulting to AWSCURRENT.
+func (s SecretsManagerSecretProvider) GetSecret(key string) (string, error) {
+	secretKey, err := parseAWSSecretKey(key, secretsManagerARNParts)
+	if err != nil {
+		return "", err
+	}
+	region := secretKey.Region
+	if region == "" {
+		region = s.secretsManagerConfig.Region
+	}
+	client, err := s.secretsManagerClient(s.secretsManagerConfig.Account, region)
+	if err != nil {
+		return "", err
+	}
+
+	input := &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretKey.ID),
+	}
+	if secretKey.Version != "" {
+		input.VersionStage = aws.String(secretKey.Version)
+	}
+	output, err := client.GetSecretValue(context.TODO(), input)
+	if err != nil {
+		return "", err
+	}
+	if output.SecretString == nil {
+		return "", fmt.Errorf("secret %s is binary, only string secrets are supported", secretKey.ID)
+	}
+	return jsonSecretValue(*output.SecretString, secretKey.JSONKey)
+}
@@ -0,0 +1,633 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+var snsConfigColumns = []string{
+	"content_based_deduplication",
+	"display_name",
+	"encryption_key_id",
+	"subscriptions",
+	"tags",
+}
+
+type SNSProvider struct {
+	database  *gorm.DB
+	awsConfig configs.AWSConfig
+}
+
+func InitSNSProvider(db *gorm.DB, awsConfig configs.AWSConfig) Providers {
+	snsProvider := &SNSProvider{}
+	snsProvider.database = db
+	snsProvider.awsConfig = awsConfig
+
+	return snsProvider
+}
+
+func (s *SNSProvider) snsClient(account string, region string) (*sns.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return sns.NewFromConfig(cfg), nil
+}
+
+func (s *SNSProvider) sqsClient(account string, region string) (*sqs.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return sqs.NewFromConfig(cfg), nil
+}
+
+func (s *SNSProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.SNSTopic{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (s *SNSProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.SNSTopic
+	if prevData != nil {
+		oldData, ok = prevData.(models.SNSTopic)
+		if !ok {
+			err := errors.New("conversion to sns topic failed")
+			return nil, err
+		}
+	}
+
+	if prevData != nil && (processed.Account != oldData.Account || processed.Region != oldData.Region) {
+		err := errors.New("account and region of an sns topic cannot be changed")
+		return nil, err
+	}
+	if prevData != nil && processed.Fifo != oldData.Fifo {
+		err := errors.New("fifo setting of an sns topic cannot be changed")
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (s *SNSProvider) InstallComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	if topic.Fifo != strings.HasSuffix(topic.Name, ".fifo") {
+		err := errors.New("names of fifo topics, and only fifo topics, must end with .fifo")
+		return err
+	}
+
+	client, err := s.snsClient(topic.Account, topic.Region)
+	if err != nil {
+		return err
+	}
+
+	input := sns.CreateTopicInput{
+		Name:       &topic.Name,
+		Attributes: topicAttributes(topic),
+	}
+	for name, value := range input.Attributes {
+		if value == "" {
+			delete(input.Attributes, name)
+		}
+	}
+	if topic.Fifo {
+		input.Attributes["FifoTopic"] = "true"
+	}
+	if len(topic.Tags) > 0 {
+		input.Tags = snsTags(topic.Tags)
+	}
+	output, err := client.CreateTopic(context.TODO(), &input)
+	if err != nil {
+		return err
+	}
+
+	return s.reconcileSubscriptions(context.TODO(), client, topic, *output.TopicArn)
+}
+
+func (s *SNSProvider) UpdateComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	client, err := s.snsClient(topic.Account, topic.Region)
+	if err != nil {
+		return err
+	}
+
+	topicARN, err := s.getTopicARN(context.TODO(), client, topic)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range topicAttributes(topic) {
+		attributeName := name
+		attributeValue := value
+		_, err = client.SetTopicAttributes(context.TODO(), &sns.SetTopicAttributesInput{
+			TopicArn:       &topicARN,
+			AttributeName:  &attributeName,
+			AttributeValue: &attributeValue,
+		})
+		if err != nil {
+			return fmt.Errorf("setting %s of sns topic %s: %w", name, topic.Name, err)
+		}
+	}
+
+	err = s.reconcileTags(context.TODO(), client, topicARN, topic.Tags)
+	if err != nil {
+		return err
+	}
+
+	return s.reconcileSubscriptions(context.TODO(), client, topic, topicARN)
+}
+
+func (s *SNSProvider) UninstallComponent(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	client, err := s.snsClient(topic.Account, topic.Region)
+	if err != nil {
+		return err
+	}
+
+	topicARN, err := s.getTopicARN(context.TODO(), client, topic)
+	if err != nil {
+		return err
+	}
+
+	// dropping every subscription also revokes the topic's access to the queues
+	topic.Subscriptions = nil
+	err = s.reconcileSubscriptions(context.TODO(), client, topic, topicARN)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteTopic(context.TODO(), &sns.DeleteTopicInput{
+		TopicArn: &topicARN,
+	})
+	return err
+}
+
+func (s *SNSProvider) GetStatus(topicInterface interface{}) (responses.ComponentStatus, error) {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: topic.Name,
+	}
+
+	client, err := s.snsClient(topic.Account, topic.Region)
+	if err != nil {
+		return status, err
+	}
+
+	topicARN, err := s.getTopicARN(context.TODO(), client, topic)
+	if err != nil {
+		return status, err
+	}
+
+	output, err := client.GetTopicAttributes(context.TODO(), &sns.GetTopicAttributesInput{
+		TopicArn: &topicARN,
+	})
+	if err != nil {
+		return status, err
+	}
+
+	status.Healthy = true
+	status.Status = "available"
+	status.Detail = responses.SNSStatus{
+		Name:                   topic.Name,
+		ARN:                    topicARN,
+		SubscriptionsConfirmed: output.Attributes["SubscriptionsConfirmed"],
+		SubscriptionsPending:   output.Attributes["SubscriptionsPending"],
+	}
+	return status, nil
+}
+
+func (s *SNSProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := s.database.Model(&models.SNSTopic{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (s *SNSProvider) Add(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	result := s.database.Create(&topic)
+	return result.Error
+}
+
+func (s *SNSProvider) Remove(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	result := s.database.Delete(&models.SNSTopic{}, "name = ?", topic.Name)
+	return result.Error
+}
+
+func (s *SNSProvider) Update(topicInterface interface{}) error {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return err
+	}
+
+	return updateByName(s.database, &topic, topic.Name, snsConfigColumns)
+}
+
+func (s *SNSProvider) GetDetail(releaseName string) (interface{}, error) {
+	var topic models.SNSTopic
+	result := s.database.Where("name = ?", releaseName).First(&topic)
+	return topic, result.Error
+}
+
+func (s *SNSProvider) GetDetailFromComponent(topicInterface interface{}) (interface{}, error) {
+	topic, ok := topicInterface.(models.SNSTopic)
+	if !ok {
+		err := errors.New("conversion to sns topic failed")
+		return nil, err
+	}
+
+	return s.GetDetail(topic.Name)
+}
+
+func (s *SNSProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var topics []models.SNSTopic
+	result := s.database.Where("module_release_id = ?", ModuleReleaseID).Find(&topics)
+
+	var topicInterface []interface{} = make([]interface{}, len(topics))
+	for i, v := range topics {
+		topicInterface[i] = v
+	}
+
+	return topicInterface, result.Error
+}
+
+func topicAttributes(topic models.SNSTopic) map[string]string {
+	attributes := map[string]string{
+		"DisplayName":    topic.DisplayName,
+		"KmsMasterKeyId": topic.EncryptionKeyID,
+	}
+	if topic.Fifo {
+		attributes["ContentBasedDeduplication"] = strconv.FormatBool(topic.ContentBasedDeduplication)
+	}
+	return attributes
+}
+
+// getTopicARN finds the topic among the topics of the account and region.
+func (s *SNSProvider) getTopicARN(ctx context.Context, client *sns.Client, topic models.SNSTopic) (string, error) {
+	input := sns.ListTopicsInput{}
+	for {
+		output, err := client.ListTopics(ctx, &input)
+		if err != nil {
+			return "", err
+		}
+		for _, listed := range output.Topics {
+			arn := aws.ToString(listed.TopicArn)
+			if strings.HasSuffix(arn, ":"+topic.Name) {
+				return arn, nil
+			}
+		}
+		if output.NextToken == nil {
+			return "", fmt.Errorf("sns topic %s not found", topic.Name)
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func (s *SNSProvider) reconcileTags(ctx context.Context, client *sns.Client, topicARN string, tags map[string]string) error {
+	current, err := client.ListTagsForResource(ctx, &sns.ListTagsForResourceInput{
+		ResourceArn: &topicARN,
+	})
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	for _, tag := range current.Tags {
+		if _, ok := tags[*tag.Key]; !ok {
+			removed = append(removed, *tag.Key)
+		}
+	}
+	if len(removed) > 0 {
+		_, err = client.UntagResource(ctx, &sns.UntagResourceInput{
+			ResourceArn: &topicARN,
+			TagKeys:     removed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(tags) > 0 {
+		_, err = client.TagResource(ctx, &sns.TagResourceInput{
+			ResourceArn: &topicARN,
+			Tags:        snsTags(tags),
+		})
+	}
+	return err
+}
+
+func snsTags(tags map[string]string) []types.Tag {
+	var snsTags []types.Tag
+	for key, value := range tags {
+		snsTags = append(snsTags, types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	return snsTags
+}
+
+// reconcileSubscriptions subscribes the declared queues to the topic and
+// unsubscribes every other sqs subscription of the topic. Each subscribed
+// queue gets a policy statement allowing the topic to send messages to it.
+func (s *SNSProvider) reconcileSubscriptions(ctx context.Context, client *sns.Client, topic models.SNSTopic, topicARN string) error {
+	sqsClient, err := s.sqsClient(topic.Account, topic.Region)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.listSubscriptions(ctx, client, topicARN)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	for _, subscription := range topic.Subscriptions {
+		queueURL, queueARN, err := s.getQueue(ctx, sqsClient, subscription.Queue)
+		if err != nil {
+			return fmt.Errorf("subscribing queue %s to sns topic %s: %w", subscription.Queue, topic.Name, err)
+		}
+		desired[queueARN] = true
+
+		err = s.allowTopic(ctx, sqsClient, queueURL, queueARN, topic.Name, topicARN)
+		if err != nil {
+			return err
+		}
+
+		attributes := map[string]string{
+			"RawMessageDelivery": strconv.FormatBool(subscription.RawMessageDelivery),
+		}
+		if subscription.FilterPolicy != "" {
+			attributes["FilterPolicy"] = subscription.FilterPolicy
+		}
+
+		subscriptionARN, ok := existing[queueARN]
+		if !ok {
+			_, err = client.Subscribe(ctx, &sns.SubscribeInput{
+				TopicArn:              &topicARN,
+				Protocol:              aws.String("sqs"),
+				Endpoint:              &queueARN,
+				Attributes:            attributes,
+				ReturnSubscriptionArn: true,
+			})
+			if err != nil {
+				return fmt.Errorf("subscribing queue %s to sns topic %s: %w", subscription.Queue, topic.Name, err)
+			}
+			continue
+		}
+
+		// an empty filter policy cannot be set, so a removed one is replaced by a match-all policy
+		if subscription.FilterPolicy == "" {
+			attributes["FilterPolicy"] = "{}"
+		}
+		for name, value := range attributes {
+			attributeName := name
+			attributeValue := value
+			_, err = client.SetSubscriptionAttributes(ctx, &sns.SetSubscriptionAttributesInput{
+				SubscriptionArn: &subscriptionARN,
+				AttributeName:   &attributeName,
+				AttributeValue:  &attributeValue,
+			})
+			if err != nil {
+				return fmt.Errorf("setting %s of queue %s subscription: %w", name, subscription.Queue, err)
+			}
+		}
+	}
+
+	for queueARN, subscriptionARN := range existing {
+		if desired[queueARN] {
+			continue
+		}
+		if subscriptionARN != "PendingConfirmation" {
+			_, err = client.Unsubscribe(ctx, &sns.UnsubscribeInput{
+				SubscriptionArn: aws.String(subscriptionARN),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// the queue may have been removed already, in which case there is no policy left to clean
+		queueURL, _, err := s.getQueue(ctx, sqsClient, queueARN[strings.LastIndex(queueARN, ":")+1:])
+		if err != nil {
+			continue
+		}
+		err = s.revokeTopic(ctx, sqsClient, queueURL, topic.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSubscriptions returns the sqs subscriptions of the topic keyed by queue arn.
+func (s *SNSProvider) listSubscriptions(ctx context.Context, client *sns.Client, topicARN string) (map[string]string, error) {
+	subscriptions := map[string]string{}
+	input := sns.ListSubscriptionsByTopicInput{
+		TopicArn: &topicARN,
+	}
+	for {
+		output, err := client.ListSubscriptionsByTopic(ctx, &input)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range output.Subscriptions {
+			if aws.ToString(subscription.Protocol) != "sqs" {
+				continue
+			}
+			subscriptions[aws.ToString(subscription.Endpoint)] = aws.ToString(subscription.SubscriptionArn)
+		}
+		if output.NextToken == nil {
+			return subscriptions, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func (s *SNSProvider) getQueue(ctx context.Context, client *sqs.Client, name string) (string, string, error) {
+	urlOutput, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &name,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	attributesOutput, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       urlOutput.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return *urlOutput.QueueUrl, attributesOutput.Attributes[string(sqstypes.QueueAttributeNameQueueArn)], nil
+}
+
+// allowTopic adds a statement to the queue policy allowing the topic to send
+// messages to the queue, keeping the statements managed elsewhere.
+func (s *SNSProvider) allowTopic(ctx context.Context, client *sqs.Client, queueURL string, queueARN string, topicName string, topicARN string) error {
+	policy, err := s.getQueuePolicy(ctx, client, queueURL)
+	if err != nil {
+		return err
+	}
+
+	statement := map[string]interface{}{
+		"Sid":       queueStatementID(topicName),
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueARN,
+		"Condition": map[string]interface{}{
+			"ArnEquals": map[string]string{"aws:SourceArn": topicARN},
+		},
+	}
+	policy["Statement"] = append(withoutStatement(policy["Statement"], topicName), statement)
+
+	return s.setQueuePolicy(ctx, client, queueURL, policy)
+}
+
+func (s *SNSProvider) revokeTopic(ctx context.Context, client *sqs.Client, queueURL string, topicName string) error {
+	policy, err := s.getQueuePolicy(ctx, client, queueURL)
+	if err != nil {
+		return err
+	}
+
+	statements := withoutStatement(policy["Statement"], topicName)
+	if len(statements) == 0 {
+		_, err = client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+			QueueUrl:   &queueURL,
+			Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): ""},
+		})
+		return err
+	}
+	policy["Statement"] = statements
+
+	return s.setQueuePolicy(ctx, client, queueURL, policy)
+}
+
+func (s *SNSProvider) getQueuePolicy(ctx context.Context, client *sqs.Client, queueURL string) (map[string]interface{}, error) {
+	output, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       &queueURL,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNamePolicy},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+	}
+	if raw := output.Attributes[string(sqstypes.QueueAttributeNamePolicy)]; raw != "" {
+		err = json.Unmarshal([]byte(raw), &policy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing queue policy: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+func (s *SNSProvider) setQueuePolicy(ctx context.Context, client *sqs.Client, queueURL string, policy map[string]interface{}) error {
+	policyStr, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   &queueURL,
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): string(policyStr)},
+	})
+	return err
+}
+
+// withoutStatement returns the policy statements except the one managed for the topic.
+func withoutStatement(statements interface{}, topicName string) []interface{} {
+	var kept []interface{}
+	switch value := statements.(type) {
+	case []interface{}:
+		kept = value
+	case map[string]interface{}:
+		kept = []interface{}{value}
+	}
+
+	var filtered []interface{}
+	for _, statement := range kept {
+		parsed, ok := statement.(map[string]interface{})
+		if ok && parsed["Sid"] == queueStatementID(topicName) {
+			continue
+		}
+		filtered = append(filtered, statement)
+	}
+	return filtered
+}
+
+func queueStatementID(topicName string) string {
+	return "sns-" + strings.ReplaceAll(topicName, ".", "-")
+}
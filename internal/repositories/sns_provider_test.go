@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithoutStatement(t *testing.T) {
+	managed := map[string]interface{}{"Sid": "sns-orders-fifo", "Effect": "Allow"}
+	other := map[string]interface{}{"Sid": "other", "Effect": "Allow"}
+	anonymous := map[string]interface{}{"Effect": "Deny"}
+
+	tests := []struct {
+		name       string
+		statements interface{}
+		want       []interface{}
+	}{
+		{name: "no statements", statements: nil, want: nil},
+		{name: "only the managed statement", statements: []interface{}{managed}, want: nil},
+		{name: "keeps the others", statements: []interface{}{other, managed, anonymous}, want: []interface{}{other, anonymous}},
+		{name: "single statement object", statements: other, want: []interface{}{other}},
+		{name: "single managed statement object", statements: managed, want: nil},
+		{name: "statement of another topic", statements: []interface{}{map[string]interface{}{"Sid": "sns-orders"}}, want: []interface{}{map[string]interface{}{"Sid": "sns-orders"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := withoutStatement(test.statements, "orders.fifo")
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
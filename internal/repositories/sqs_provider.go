@@ -0,0 +1,450 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"gorm.io/gorm"
+)
+
+// SQS defaults, restored when an attribute is removed from the queue
+const (
+	sqsDefaultVisibilityTimeout = 30
+	sqsDefaultMessageRetention  = 345600
+	sqsDefaultMaxMessageSize    = 262144
+)
+
+var sqsConfigColumns = []string{
+	"content_based_deduplication",
+	"visibility_timeout_seconds",
+	"message_retention_seconds",
+	"delay_seconds",
+	"receive_wait_time_seconds",
+	"max_message_size",
+	"encryption_key_id",
+	"dead_letter_queue",
+	"max_receive_count",
+	"tags",
+}
+
+type SQSProvider struct {
+	database  *gorm.DB
+	awsConfig configs.AWSConfig
+}
+
+func InitSQSProvider(db *gorm.DB, awsConfig configs.AWSConfig) Providers {
+	sqsProvider := &SQSProvider{}
+	sqsProvider.database = db
+	sqsProvider.awsConfig = awsConfig
+
+	return sqsProvider
+}
+
+func (s *SQSProvider) sqsClient(account string, region string) (*sqs.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return sqs.NewFromConfig(cfg), nil
+}
+
+func (s *SQSProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.SQSQueue{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (s *SQSProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.SQSQueue
+	if prevData != nil {
+		oldData, ok = prevData.(models.SQSQueue)
+		if !ok {
+			err := errors.New("conversion to sqs queue failed")
+			return nil, err
+		}
+	}
+
+	if prevData != nil && (processed.Account != oldData.Account || processed.Region != oldData.Region) {
+		err := errors.New("account and region of an sqs queue cannot be changed")
+		return nil, err
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (s *SQSProvider) InstallComponent(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	if queue.Fifo != strings.HasSuffix(queue.Name, ".fifo") {
+		err := errors.New("names of fifo queues, and only fifo queues, must end with .fifo")
+		return err
+	}
+
+	client, err := s.sqsClient(queue.Account, queue.Region)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := s.queueAttributes(context.TODO(), client, queue)
+	if err != nil {
+		return err
+	}
+	// empty values only clear attributes on update, they are rejected on create
+	for name, value := range attributes {
+		if value == "" {
+			delete(attributes, name)
+		}
+	}
+	if queue.Fifo {
+		attributes[string(types.QueueAttributeNameFifoQueue)] = "true"
+	}
+
+	input := sqs.CreateQueueInput{
+		QueueName:  &queue.Name,
+		Attributes: attributes,
+	}
+	if len(queue.Tags) > 0 {
+		input.Tags = queue.Tags
+	}
+	_, err = client.CreateQueue(context.TODO(), &input)
+	return err
+}
+
+func (s *SQSProvider) UpdateComponent(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	client, err := s.sqsClient(queue.Account, queue.Region)
+	if err != nil {
+		return err
+	}
+
+	url, err := s.getQueueURL(context.TODO(), client, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	current, err := client.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       url,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameFifoQueue},
+	})
+	if err != nil {
+		return err
+	}
+	if (current.Attributes[string(types.QueueAttributeNameFifoQueue)] == "true") != queue.Fifo {
+		return fmt.Errorf("fifo setting of sqs queue %s cannot be changed", queue.Name)
+	}
+
+	attributes, err := s.queueAttributes(context.TODO(), client, queue)
+	if err != nil {
+		return err
+	}
+	_, err = client.SetQueueAttributes(context.TODO(), &sqs.SetQueueAttributesInput{
+		QueueUrl:   url,
+		Attributes: attributes,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.reconcileTags(context.TODO(), client, url, queue.Tags)
+}
+
+func (s *SQSProvider) UninstallComponent(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	client, err := s.sqsClient(queue.Account, queue.Region)
+	if err != nil {
+		return err
+	}
+
+	url, err := s.getQueueURL(context.TODO(), client, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteQueue(context.TODO(), &sqs.DeleteQueueInput{
+		QueueUrl: url,
+	})
+	return err
+}
+
+func (s *SQSProvider) GetStatus(queueInterface interface{}) (responses.ComponentStatus, error) {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: queue.Name,
+	}
+
+	client, err := s.sqsClient(queue.Account, queue.Region)
+	if err != nil {
+		return status, err
+	}
+
+	url, err := s.getQueueURL(context.TODO(), client, queue.Name)
+	if err != nil {
+		return status, err
+	}
+
+	output, err := client.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       url,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameAll},
+	})
+	if err != nil {
+		return status, err
+	}
+
+	status.Healthy = true
+	status.Status = "available"
+	status.Detail = responses.SQSStatus{
+		Name:                        queue.Name,
+		URL:                         *url,
+		ARN:                         output.Attributes[string(types.QueueAttributeNameQueueArn)],
+		ApproximateMessages:         output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)],
+		ApproximateMessagesInFlight: output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible)],
+		ApproximateMessagesDelayed:  output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessagesDelayed)],
+	}
+	return status, nil
+}
+
+// GetOutputs exposes the url and arn of the queue to the other components of
+// the module as "{queue}/url" and "{queue}/arn".
+func (s *SQSProvider) GetOutputs(queueInterface interface{}) (map[string]string, error) {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return nil, err
+	}
+
+	client, err := s.sqsClient(queue.Account, queue.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.getQueueURL(context.TODO(), client, queue.Name)
+	if err != nil {
+		return nil, err
+	}
+	arn, err := s.getQueueARN(context.TODO(), client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]string{
+		queue.Name + "/url": *url,
+		queue.Name + "/arn": arn,
+	}
+	return outputs, nil
+}
+
+func (s *SQSProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := s.database.Model(&models.SQSQueue{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (s *SQSProvider) Add(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	result := s.database.Create(&queue)
+	return result.Error
+}
+
+func (s *SQSProvider) Remove(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	result := s.database.Delete(&models.SQSQueue{}, "name = ?", queue.Name)
+	return result.Error
+}
+
+func (s *SQSProvider) Update(queueInterface interface{}) error {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return err
+	}
+
+	return updateByName(s.database, &queue, queue.Name, sqsConfigColumns)
+}
+
+func (s *SQSProvider) GetDetail(releaseName string) (interface{}, error) {
+	var queue models.SQSQueue
+	result := s.database.Where("name = ?", releaseName).First(&queue)
+	return queue, result.Error
+}
+
+func (s *SQSProvider) GetDetailFromComponent(queueInterface interface{}) (interface{}, error) {
+	queue, ok := queueInterface.(models.SQSQueue)
+	if !ok {
+		err := errors.New("conversion to sqs queue failed")
+		return nil, err
+	}
+
+	return s.GetDetail(queue.Name)
+}
+
+func (s *SQSProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var queues []models.SQSQueue
+	result := s.database.Where("module_release_id = ?", ModuleReleaseID).Find(&queues)
+
+	var queueInterface []interface{} = make([]interface{}, len(queues))
+	for i, v := range queues {
+		queueInterface[i] = v
+	}
+
+	return queueInterface, result.Error
+}
+
+// queueAttributes returns every managed attribute of the queue, falling back
+// to the SQS defaults so removed settings are reset on update.
+func (s *SQSProvider) queueAttributes(ctx context.Context, client *sqs.Client, queue models.SQSQueue) (map[string]string, error) {
+	attributes := map[string]string{
+		string(types.QueueAttributeNameVisibilityTimeout):             strconv.Itoa(int(withDefault(queue.VisibilityTimeoutSeconds, sqsDefaultVisibilityTimeout))),
+		string(types.QueueAttributeNameMessageRetentionPeriod):        strconv.Itoa(int(withDefault(queue.MessageRetentionSeconds, sqsDefaultMessageRetention))),
+		string(types.QueueAttributeNameMaximumMessageSize):            strconv.Itoa(int(withDefault(queue.MaxMessageSize, sqsDefaultMaxMessageSize))),
+		string(types.QueueAttributeNameDelaySeconds):                  strconv.Itoa(int(queue.DelaySeconds)),
+		string(types.QueueAttributeNameReceiveMessageWaitTimeSeconds): strconv.Itoa(int(queue.ReceiveWaitTimeSeconds)),
+		string(types.QueueAttributeNameKmsMasterKeyId):                queue.EncryptionKeyID,
+		string(types.QueueAttributeNameRedrivePolicy):                 "",
+	}
+	if queue.Fifo {
+		attributes[string(types.QueueAttributeNameContentBasedDeduplication)] = strconv.FormatBool(queue.ContentBasedDeduplication)
+	}
+
+	if queue.DeadLetterQueue != "" {
+		dlqURL, err := s.getQueueURL(ctx, client, queue.DeadLetterQueue)
+		if err != nil {
+			return nil, fmt.Errorf("dead letter queue %s: %w", queue.DeadLetterQueue, err)
+		}
+		dlqARN, err := s.getQueueARN(ctx, client, dlqURL)
+		if err != nil {
+			return nil, err
+		}
+
+		redrivePolicy, err := json.Marshal(map[string]string{
+			"deadLetterTargetArn": dlqARN,
+			"maxReceiveCount":     strconv.Itoa(int(withDefault(queue.MaxReceiveCount, 1))),
+		})
+		if err != nil {
+			return nil, err
+		}
+		attributes[string(types.QueueAttributeNameRedrivePolicy)] = string(redrivePolicy)
+	}
+	return attributes, nil
+}
+
+func (s *SQSProvider) reconcileTags(ctx context.Context, client *sqs.Client, url *string, tags map[string]string) error {
+	current, err := client.ListQueueTags(ctx, &sqs.ListQueueTagsInput{
+		QueueUrl: url,
+	})
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	for key := range current.Tags {
+		if _, ok := tags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) > 0 {
+		_, err = client.UntagQueue(ctx, &sqs.UntagQueueInput{
+			QueueUrl: url,
+			TagKeys:  removed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(tags) > 0 {
+		_, err = client.TagQueue(ctx, &sqs.TagQueueInput{
+			QueueUrl: url,
+			Tags:     tags,
+		})
+	}
+	return err
+}
+
+func (s *SQSProvider) getQueueURL(ctx context.Context, client *sqs.Client, name string) (*string, error) {
+	output, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.QueueUrl, nil
+}
+
+func (s *SQSProvider) getQueueARN(ctx context.Context, client *sqs.Client, url *string) (string, error) {
+	output, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       url,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.Attributes[string(types.QueueAttributeNameQueueArn)], nil
+}
+
+func withDefault(value int32, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/awsconfig"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+// ssmARNParts is the number of parts of
+// arn:aws:ssm:region:account:parameter/name.
+const ssmARNParts = 6
+
+type SSMSecretProvider struct {
+	awsConfig configs.AWSConfig
+	ssmConfig configs.SSMConfig
+}
+
+func InitSSMSecretProvider(awsConfig configs.AWSConfig, ssmConfig configs.SSMConfig) SecretProviders {
+	ssmSecretProvider := &SSMSecretProvider{}
+	ssmSecretProvider.awsConfig = awsConfig
+	ssmSecretProvider.ssmConfig = ssmConfig
+	return ssmSecretProvider
+}
+
+func (s *SSMSecretProvider) ssmClient(account string, region string) (*ssm.Client, error) {
+	cfg, err := awsconfig.GetAWSConfig(s.awsConfig, account, region)
+	if err != nil {
+		return nil, err
+	}
+	return ssm.NewFromConfig(cfg), nil
+}
+
+// GetSecret reads parameter[:json-key[:version]], where the version is a
+// parameter version or label. SecureString parameters are decrypted.
+func (s SSMSecretProvider) GetSecret(key string) (string, error) {
+	secretKey, err := parseAWSSecretKey(key, ssmARNParts)
+	if err != nil {
+		return "", err
+	}
+	region := secretKey.Region
+	if region == "" {
+		region = s.ssmConfig.Region
+	}
+	client, err := s.ssmClient(s.ssmConfig.Account, region)
+	if err != nil {
+		return "", err
+	}
+
+	name := secretKey.ID
+	if secretKey.Version != "" {
+		name += ":" + secretKey.Version
+	}
+	output, err := client.GetParameter(context.TODO(), &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	return jsonSecretValue(aws.ToString(output.Parameter.Value), secretKey.JSONKey)
+}
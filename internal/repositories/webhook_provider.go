@@ -0,0 +1,474 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhook actions, recorded with the result of the call
+const (
+	webhookActionInstall   = "install"
+	webhookActionUpdate    = "update"
+	webhookActionUninstall = "uninstall"
+)
+
+// the error body is cut in error messages, it may be a whole html page
+const webhookErrorBodyLength = 512
+
+var webhookConfigColumns = []string{
+	"install",
+	"update",
+	"uninstall",
+	"outputs",
+}
+
+// webhookTemplate is the data the url, headers and body of a call are
+// rendered with. Outputs holds the outputs captured by the last call.
+type webhookTemplate struct {
+	Name     string
+	Revision int
+	Outputs  map[string]string
+}
+
+type WebhookProvider struct {
+	database        *gorm.DB
+	webhookClient   *webhook.Client
+	webhookConfig   configs.WebhookConfig
+	secretProviders map[string]SecretProviders
+}
+
+func InitWebhookProvider(db *gorm.DB, webhookClient *webhook.Client, webhookConfig configs.WebhookConfig, secretProviders map[string]SecretProviders) Providers {
+	webhookProvider := &WebhookProvider{}
+	webhookProvider.database = db
+	webhookProvider.webhookClient = webhookClient
+	webhookProvider.webhookConfig = webhookConfig
+	webhookProvider.secretProviders = secretProviders
+
+	return webhookProvider
+}
+
+func (w *WebhookProvider) Convert(rawData interface{}) (interface{}, error) {
+	jsonStr, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	component := models.Webhook{}
+	err = json.Unmarshal(jsonStr, &component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+func (w *WebhookProvider) PreProcess(data interface{}, prevData interface{}, module interface{}, moduleRelease interface{}) (interface{}, error) {
+	processed, ok := data.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return nil, err
+	}
+	releaseParsed, ok := moduleRelease.(models.ModuleRelease)
+	if !ok {
+		err := errors.New("conversion to release failed")
+		return nil, err
+	}
+
+	var oldData models.Webhook
+	if prevData != nil {
+		oldData, ok = prevData.(models.Webhook)
+		if !ok {
+			err := errors.New("conversion to webhook failed")
+			return nil, err
+		}
+	}
+
+	processed.ModuleReleaseID = releaseParsed.ID
+	processed.Revision = oldData.Revision + 1
+	return processed, nil
+}
+
+func (w *WebhookProvider) InstallComponent(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	if hook.Install.IsEmpty() {
+		return fmt.Errorf("webhook %s has no install call", hook.Name)
+	}
+	return w.apply(context.TODO(), hook, webhookActionInstall, hook.Install)
+}
+
+func (w *WebhookProvider) UpdateComponent(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	call := hook.Update
+	if call.IsEmpty() {
+		call = hook.Install
+	}
+	if call.IsEmpty() {
+		return fmt.Errorf("webhook %s has no install call", hook.Name)
+	}
+	return w.apply(context.TODO(), hook, webhookActionUpdate, call)
+}
+
+func (w *WebhookProvider) UninstallComponent(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	if hook.Uninstall.IsEmpty() {
+		return nil
+	}
+	_, err := w.call(context.TODO(), hook, webhookActionUninstall, hook.Uninstall)
+	return err
+}
+
+// GetStatus reports the last successful call, the external system is not
+// queried as the webhook does not declare how to.
+func (w *WebhookProvider) GetStatus(webhookInterface interface{}) (responses.ComponentStatus, error) {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return responses.ComponentStatus{}, err
+	}
+	status := responses.ComponentStatus{
+		Name: hook.Name,
+	}
+
+	var result models.WebhookResult
+	err := w.database.Where("webhook_name = ?", hook.Name).First(&result).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		status.Status = "pending"
+		status.Message = "no call of the webhook succeeded yet"
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	status.Detail = responses.WebhookStatus{
+		Name:       hook.Name,
+		Action:     result.Action,
+		StatusCode: result.StatusCode,
+		Outputs:    result.Outputs,
+		CalledAt:   result.UpdatedAt,
+	}
+	status.Healthy = true
+	status.Status = "applied"
+	return status, nil
+}
+
+// GetOutputs exposes the values captured from the response of the last call
+// to the other components of the module as "{webhook}/{output}".
+func (w *WebhookProvider) GetOutputs(webhookInterface interface{}) (map[string]string, error) {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return nil, err
+	}
+
+	var result models.WebhookResult
+	err := w.database.Where("webhook_name = ?", hook.Name).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]string{}
+	for key, value := range result.Outputs {
+		outputs[hook.Name+"/"+key] = value
+	}
+	return outputs, nil
+}
+
+func (w *WebhookProvider) GetAllName() ([]string, error) {
+	var names []string
+	result := w.database.Model(&models.Webhook{}).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (w *WebhookProvider) Add(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	result := w.database.Create(&hook)
+	return result.Error
+}
+
+func (w *WebhookProvider) Remove(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	result := w.database.Unscoped().Delete(&models.WebhookResult{}, "webhook_name = ?", hook.Name)
+	if result.Error != nil {
+		return result.Error
+	}
+	result = w.database.Delete(&models.Webhook{}, "name = ?", hook.Name)
+	return result.Error
+}
+
+func (w *WebhookProvider) Update(webhookInterface interface{}) error {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return err
+	}
+
+	return updateByName(w.database, &hook, hook.Name, webhookConfigColumns)
+}
+
+func (w *WebhookProvider) GetDetail(releaseName string) (interface{}, error) {
+	var hook models.Webhook
+	result := w.database.Where("name = ?", releaseName).First(&hook)
+	return hook, result.Error
+}
+
+func (w *WebhookProvider) GetDetailFromComponent(webhookInterface interface{}) (interface{}, error) {
+	hook, ok := webhookInterface.(models.Webhook)
+	if !ok {
+		err := errors.New("conversion to webhook failed")
+		return nil, err
+	}
+
+	return w.GetDetail(hook.Name)
+}
+
+func (w *WebhookProvider) GetFromModuleReleaseID(ModuleReleaseID uint) ([]interface{}, error) {
+	var hooks []models.Webhook
+	result := w.database.Where("module_release_id = ?", ModuleReleaseID).Find(&hooks)
+
+	var webhookInterface []interface{} = make([]interface{}, len(hooks))
+	for i, v := range hooks {
+		webhookInterface[i] = v
+	}
+
+	return webhookInterface, result.Error
+}
+
+// apply makes the call and records the outputs captured from its response.
+func (w *WebhookProvider) apply(ctx context.Context, hook models.Webhook, action string, call models.WebhookCall) error {
+	response, err := w.call(ctx, hook, action, call)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := captureOutputs(hook.Outputs, response.Body)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", hook.Name, err)
+	}
+
+	result := models.WebhookResult{
+		WebhookName: hook.Name,
+		Action:      action,
+		StatusCode:  response.StatusCode,
+		Outputs:     outputs,
+	}
+	return w.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "status_code", "outputs", "updated_at"}),
+	}).Create(&result).Error
+}
+
+// call renders and makes the call, retrying when no response was received or
+// the server answered with 429 or a 5xx status.
+func (w *WebhookProvider) call(ctx context.Context, hook models.Webhook, action string, call models.WebhookCall) (webhook.Response, error) {
+	request, err := w.render(hook, call)
+	if err != nil {
+		return webhook.Response{}, fmt.Errorf("webhook %s %s: %w", action, hook.Name, err)
+	}
+
+	retries := callRetries(call, w.webhookConfig.Retries)
+
+	for attempt := 0; ; attempt++ {
+		response, err := w.webhookClient.Do(ctx, request)
+		if err == nil && expectedStatus(call.ExpectedStatus, response.StatusCode) {
+			return response, nil
+		}
+		if err == nil {
+			body := string(response.Body)
+			if len(body) > webhookErrorBodyLength {
+				body = body[:webhookErrorBodyLength]
+			}
+			err = fmt.Errorf("webhook %s %s returned %d: %s", action, hook.Name, response.StatusCode, body)
+			if response.StatusCode != http.StatusTooManyRequests && response.StatusCode < 500 {
+				return response, err
+			}
+		}
+		if attempt >= retries {
+			return response, err
+		}
+		time.Sleep(w.webhookConfig.RetryInterval)
+	}
+}
+
+// render executes the url, headers and body of the call as templates. They
+// use [[ ]] delimiters so they are left alone by the module templating.
+func (w *WebhookProvider) render(hook models.Webhook, call models.WebhookCall) (webhook.Request, error) {
+	data := webhookTemplate{
+		Name:     hook.Name,
+		Revision: hook.Revision,
+		Outputs:  map[string]string{},
+	}
+	var result models.WebhookResult
+	err := w.database.Where("webhook_name = ?", hook.Name).First(&result).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook.Request{}, err
+	}
+	for key, value := range result.Outputs {
+		data.Outputs[key] = value
+	}
+
+	request := webhook.Request{
+		Method:  call.Method,
+		Headers: map[string]string{},
+	}
+	request.URL, err = renderWebhookTemplate(call.URL, data)
+	if err != nil {
+		return request, err
+	}
+	for key, value := range call.Headers {
+		request.Headers[key], err = renderWebhookTemplate(value, data)
+		if err != nil {
+			return request, err
+		}
+	}
+	for key, reference := range call.HeaderSecrets {
+		if _, ok := call.Headers[key]; ok {
+			return request, fmt.Errorf("header %s is both a value and a secret", key)
+		}
+		request.Headers[key], err = resolveSecret(w.secretProviders, reference)
+		if err != nil {
+			return request, fmt.Errorf("header %s: %w", key, err)
+		}
+	}
+	request.Body, err = renderWebhookTemplate(call.Body, data)
+	return request, err
+}
+
+func renderWebhookTemplate(text string, data webhookTemplate) (string, error) {
+	// the environment of the controller holds its credentials
+	functions := sprig.TxtFuncMap()
+	delete(functions, "env")
+	delete(functions, "expandenv")
+
+	tmpl, err := template.New("webhook").Delims("[[", "]]").Funcs(functions).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, data)
+	return buf.String(), err
+}
+
+// callRetries is the number of retries of the call. A call whose method is
+// not idempotent may have been applied before failing, so it is only retried
+// when its retries are set. No method means POST.
+func callRetries(call models.WebhookCall, defaultRetries int) int {
+	if call.Retries != nil {
+		return *call.Retries
+	}
+	switch strings.ToUpper(call.Method) {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return defaultRetries
+	}
+	return 0
+}
+
+func expectedStatus(expected []int, statusCode int) bool {
+	if len(expected) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, code := range expected {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// captureOutputs extracts the declared outputs from a JSON response. Paths
+// are dot separated keys and array indexes, such as "data.items.0.id", and an
+// empty path captures the whole body.
+func captureOutputs(paths map[string]string, body []byte) (map[string]string, error) {
+	outputs := map[string]string{}
+	if len(paths) == 0 {
+		return outputs, nil
+	}
+
+	var document interface{}
+	parseErr := json.Unmarshal(body, &document)
+	for name, path := range paths {
+		if path == "" {
+			outputs[name] = string(body)
+			continue
+		}
+		if parseErr != nil {
+			return nil, fmt.Errorf("output %s: response is not json: %w", name, parseErr)
+		}
+
+		value := document
+		for _, key := range strings.Split(path, ".") {
+			switch current := value.(type) {
+			case map[string]interface{}:
+				next, ok := current[key]
+				if !ok {
+					return nil, fmt.Errorf("output %s: %s not found in response", name, path)
+				}
+				value = next
+			case []interface{}:
+				index, err := strconv.Atoi(key)
+				if err != nil || index < 0 || index >= len(current) {
+					return nil, fmt.Errorf("output %s: %s not found in response", name, path)
+				}
+				value = current[index]
+			default:
+				return nil, fmt.Errorf("output %s: %s not found in response", name, path)
+			}
+		}
+
+		switch v := value.(type) {
+		case nil:
+			outputs[name] = ""
+		case string:
+			outputs[name] = v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			outputs[name] = string(data)
+		}
+	}
+	return outputs, nil
+}
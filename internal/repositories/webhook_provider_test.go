@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+func TestCaptureOutputs(t *testing.T) {
+	body := []byte(`{"data": {"id": "abc", "count": 3, "empty": null, "items": [{"id": "first"}, {"id": "second"}], "tags": {"team": "data"}}}`)
+
+	tests := []struct {
+		name    string
+		paths   map[string]string
+		body    []byte
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "no outputs", body: []byte("not json"), want: map[string]string{}},
+		{name: "whole body", paths: map[string]string{"raw": ""}, body: []byte("not json"), want: map[string]string{"raw": "not json"}},
+		{name: "string", paths: map[string]string{"id": "data.id"}, body: body, want: map[string]string{"id": "abc"}},
+		{name: "array index", paths: map[string]string{"id": "data.items.1.id"}, body: body, want: map[string]string{"id": "second"}},
+		{name: "number", paths: map[string]string{"count": "data.count"}, body: body, want: map[string]string{"count": "3"}},
+		{name: "null", paths: map[string]string{"empty": "data.empty"}, body: body, want: map[string]string{"empty": ""}},
+		{name: "object", paths: map[string]string{"tags": "data.tags"}, body: body, want: map[string]string{"tags": `{"team":"data"}`}},
+		{name: "missing key", paths: map[string]string{"id": "data.missing"}, body: body, wantErr: true},
+		{name: "index out of range", paths: map[string]string{"id": "data.items.2.id"}, body: body, wantErr: true},
+		{name: "key of a string", paths: map[string]string{"id": "data.id.value"}, body: body, wantErr: true},
+		{name: "not json", paths: map[string]string{"id": "data.id"}, body: []byte("not json"), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := captureOutputs(test.paths, test.body)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCallRetries(t *testing.T) {
+	none := 0
+	two := 2
+
+	tests := []struct {
+		name string
+		call models.WebhookCall
+		want int
+	}{
+		{name: "default method", call: models.WebhookCall{}, want: 0},
+		{name: "post", call: models.WebhookCall{Method: "POST"}, want: 0},
+		{name: "patch", call: models.WebhookCall{Method: "PATCH"}, want: 0},
+		{name: "post with retries", call: models.WebhookCall{Method: "POST", Retries: &two}, want: 2},
+		{name: "get", call: models.WebhookCall{Method: "GET"}, want: 3},
+		{name: "lower case put", call: models.WebhookCall{Method: "put"}, want: 3},
+		{name: "delete", call: models.WebhookCall{Method: "DELETE"}, want: 3},
+		{name: "delete without retries", call: models.WebhookCall{Method: "DELETE", Retries: &none}, want: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := callRetries(test.call, 3)
+			if got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
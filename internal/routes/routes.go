@@ -4,14 +4,19 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/airflow"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/controllers"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/database"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/helm"
-	"github.com/gudangada/data-warehouse/warehouse-controller/internal/kinesis"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/kafka"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/kafkaconnect"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/schemaregistry"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/services"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/vault"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/webhook"
+	"github.com/hashicorp/vault/api"
 )
 
 type Route struct{}
@@ -24,67 +29,245 @@ func (r *Route) Init(config configs.AppConfigs) *mux.Router {
 
 	chartRepo := helm.GetChartRepo(config.ChartRepo)
 
-	kinesisClient, err := kinesis.GetKinesisClient()
+	restConfig, err := helm.GetRestConfig(config.Kubernetes)
 	if err != nil {
 		panic(err)
 	}
 
+	kafkaClient := kafka.GetKafkaClient(config.Kafka)
+	connectClient := kafkaconnect.GetKafkaConnectClient(config.KafkaConnect)
+	registryClient := schemaregistry.GetSchemaRegistryClient(config.SchemaRegistry)
+	webhookClient := webhook.GetWebhookClient(config.Webhook)
+	airflowClient := airflow.GetAirflowClient(config.Airflow)
+
 	database, err := database.GetDB(config.Database)
 	if err != nil {
 		panic(err)
 	}
 
-	vault, err := vault.GetVaultSecret(config.Vault)
-	if err != nil {
-		panic(err)
+	var vaultClient *api.Client
+	if config.Vault.URL != "" {
+		vaultClient, err = vault.GetVaultSecret(config.Vault)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	defaultNamespace := config.Kubernetes.DefaultNamespace
 
-	moduleRepository := repositories.InitModuleRepository(database)
+	secretProviders := map[string]repositories.SecretProviders{}
+	if vaultClient != nil {
+		secretProviders["vault"] = repositories.InitVaultSecretProvider(vaultClient)
+	}
+	if config.SecretsManager.Enabled {
+		secretProviders["aws-secretsmanager"] = repositories.InitSecretsManagerSecretProvider(config.AWS, config.SecretsManager)
+	}
+	if config.SSM.Enabled {
+		secretProviders["ssm"] = repositories.InitSSMSecretProvider(config.AWS, config.SSM)
+	}
+	if config.Kubernetes.SecretProvider {
+		secretProviders["kubernetes"], err = repositories.InitClusterSecretProvider(restConfig, config.Kubernetes.AvailableNamespace)
+		if err != nil {
+			panic(err)
+		}
+	}
+	if config.SecretFile.Path != "" {
+		secretProviders["file"] = repositories.InitFileSecretProvider(config.SecretFile)
+	}
 
-	chartProvider := repositories.InitChartProvider(helmClient, database, defaultNamespace, chartRepo)
-	kinesisProvider := repositories.InitKinesisProvider(database, kinesisClient)
+	moduleRepository := repositories.InitModuleRepository(database)
+	chartArchiveRepository := repositories.InitChartArchiveRepository(database)
 
-	vaultSecretProvider := repositories.InitVaultSecretProvider(vault)
+	chartProvider := repositories.InitChartProvider(helmClient, database, defaultNamespace, chartRepo, config.Helm, chartArchiveRepository)
+	kinesisProvider := repositories.InitKinesisProvider(database, config.AWS, config.Kinesis)
+	firehoseProvider := repositories.InitFirehoseProvider(database, config.AWS, config.Kinesis, secretProviders)
+	s3Provider := repositories.InitS3Provider(database, config.AWS)
+	sqsProvider := repositories.InitSQSProvider(database, config.AWS)
+	snsProvider := repositories.InitSNSProvider(database, config.AWS)
+	kafkaProvider := repositories.InitKafkaProvider(database, kafkaClient, config.Kafka)
+	connectorProvider := repositories.InitConnectorProvider(database, connectClient, config.KafkaConnect, secretProviders)
+	schemaProvider := repositories.InitSchemaProvider(database, registryClient)
+	postgresProvider := repositories.InitPostgresProvider(database, vaultClient, config.Postgres)
+	dynamodbProvider := repositories.InitDynamoDBProvider(database, config.AWS, config.DynamoDB)
+	webhookProvider := repositories.InitWebhookProvider(database, webhookClient, config.Webhook, secretProviders)
+	manifestProvider, err := repositories.InitManifestProvider(database, restConfig, defaultNamespace, config.Kubernetes.AvailableNamespace, config.Kubernetes.ClusterKinds)
+	if err != nil {
+		panic(err)
+	}
 
-	componentProviders := map[string]repositories.Providers{
-		"chart":   chartProvider,
-		"kinesis": kinesisProvider,
+	airflowProvider := repositories.InitAirflowProvider(database, airflowClient, secretProviders)
+	kubernetesSecretProvider, err := repositories.InitKubernetesSecretProvider(database, restConfig, defaultNamespace, config.Kubernetes.AvailableNamespace, secretProviders)
+	if err != nil {
+		panic(err)
 	}
 
-	secretProviders := map[string]repositories.SecretProviders{
-		"vault": vaultSecretProvider,
+	componentProviders := map[string]repositories.Providers{
+		"chart":      chartProvider,
+		"kinesis":    kinesisProvider,
+		"firehose":   firehoseProvider,
+		"s3":         s3Provider,
+		"sqs":        sqsProvider,
+		"sns":        snsProvider,
+		"kafka":      kafkaProvider,
+		"manifest":   manifestProvider,
+		"connector":  connectorProvider,
+		"schema":     schemaProvider,
+		"postgres":   postgresProvider,
+		"dynamodb":   dynamodbProvider,
+		"webhook":    webhookProvider,
+		"airflow":    airflowProvider,
+		"k8s-secret": kubernetesSecretProvider,
 	}
 
-	chartService := services.InitChartService(chartProvider)
-	kinesisService := services.InitKinesisService(kinesisProvider)
+	chartService := services.InitChartService(chartProvider, moduleRepository, chartArchiveRepository)
+	kinesisService := services.InitKinesisService(kinesisProvider, moduleRepository)
+	firehoseService := services.InitFirehoseService(firehoseProvider)
+	s3Service := services.InitS3Service(s3Provider)
+	sqsService := services.InitSQSService(sqsProvider)
+	snsService := services.InitSNSService(snsProvider)
+	kafkaService := services.InitKafkaService(kafkaProvider)
+	manifestService := services.InitManifestService(manifestProvider)
+	connectorService := services.InitConnectorService(connectorProvider)
+	schemaService := services.InitSchemaService(schemaProvider)
+	postgresService := services.InitPostgresService(postgresProvider)
+	dynamodbService := services.InitDynamoDBService(dynamodbProvider)
+	webhookService := services.InitWebhookService(webhookProvider)
+	airflowService := services.InitAirflowService(airflowProvider)
+	kubernetesSecretService := services.InitKubernetesSecretService(kubernetesSecretProvider)
 	moduleService := services.InitModuleService(moduleRepository, componentProviders, secretProviders)
 
 	chartController := controllers.InitChartController(chartService)
 	kinesisController := controllers.InitKinesisController(kinesisService)
+	firehoseController := controllers.InitFirehoseController(firehoseService)
+	s3Controller := controllers.InitS3Controller(s3Service)
+	sqsController := controllers.InitSQSController(sqsService)
+	snsController := controllers.InitSNSController(snsService)
+	kafkaController := controllers.InitKafkaController(kafkaService)
+	manifestController := controllers.InitManifestController(manifestService)
+	connectorController := controllers.InitConnectorController(connectorService)
+	schemaController := controllers.InitSchemaController(schemaService)
+	postgresController := controllers.InitPostgresController(postgresService)
+	dynamodbController := controllers.InitDynamoDBController(dynamodbService)
+	webhookController := controllers.InitWebhookController(webhookService)
+	airflowController := controllers.InitAirflowController(airflowService)
+	kubernetesSecretController := controllers.InitKubernetesSecretController(kubernetesSecretService)
 	moduleController := controllers.InitModuleController(moduleService)
 
+	if config.Kubernetes.SecretSyncInterval > 0 {
+		go kubernetesSecretService.StartSync(config.Kubernetes.SecretSyncInterval)
+	}
+
 	router := mux.NewRouter().StrictSlash(false)
 
 	router.HandleFunc("/chart", chartController.Release).Methods(http.MethodPost)
 	router.HandleFunc("/chart", chartController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/chart/import", chartController.ImportRelease).Methods(http.MethodPost)
+	router.HandleFunc("/chart/template", chartController.TemplateRelease).Methods(http.MethodPost)
 	router.HandleFunc("/chart/{chart-name}", chartController.GetReleaseDetail).Methods(http.MethodGet)
 	router.HandleFunc("/chart/{chart-name}", chartController.UpdateRelease).Methods(http.MethodPut)
 	router.HandleFunc("/chart/{chart-name}", chartController.RemoveRelease).Methods(http.MethodDelete)
+	router.HandleFunc("/chart/{chart-name}/rollback", chartController.RollbackRelease).Methods(http.MethodPost)
+	router.HandleFunc("/chart/{chart-name}/history", chartController.GetReleaseHistory).Methods(http.MethodGet)
+	router.HandleFunc("/chart/{chart-name}/tests", chartController.GetReleaseTestResults).Methods(http.MethodGet)
+	router.HandleFunc("/chart/{chart-name}/status", chartController.GetReleaseStatus).Methods(http.MethodGet)
+
+	router.HandleFunc("/chart-archive", chartController.UploadChartArchive).Methods(http.MethodPost)
+	router.HandleFunc("/chart-archive", chartController.GetAllChartArchive).Methods(http.MethodGet)
+	router.HandleFunc("/chart-archive/{archive-name}/{archive-version}", chartController.RemoveChartArchive).Methods(http.MethodDelete)
 
 	router.HandleFunc("/kinesis", kinesisController.Release).Methods(http.MethodPost)
 	router.HandleFunc("/kinesis", kinesisController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/kinesis/import", kinesisController.ImportRelease).Methods(http.MethodPost)
 	router.HandleFunc("/kinesis/{kinesis-name}", kinesisController.GetReleaseDetail).Methods(http.MethodGet)
 	router.HandleFunc("/kinesis/{kinesis-name}", kinesisController.UpdateRelease).Methods(http.MethodPut)
 	router.HandleFunc("/kinesis/{kinesis-name}", kinesisController.RemoveRelease).Methods(http.MethodDelete)
 
+	router.HandleFunc("/firehose", firehoseController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/firehose", firehoseController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/firehose/{firehose-name}", firehoseController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/firehose/{firehose-name}", firehoseController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/firehose/{firehose-name}", firehoseController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/s3", s3Controller.Release).Methods(http.MethodPost)
+	router.HandleFunc("/s3", s3Controller.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/s3/{bucket-name}", s3Controller.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/s3/{bucket-name}", s3Controller.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/s3/{bucket-name}", s3Controller.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/sqs", sqsController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/sqs", sqsController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/sqs/{queue-name}", sqsController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/sqs/{queue-name}", sqsController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/sqs/{queue-name}", sqsController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/sns", snsController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/sns", snsController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/sns/{topic-name}", snsController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/sns/{topic-name}", snsController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/sns/{topic-name}", snsController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/kafka", kafkaController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/kafka", kafkaController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/kafka/{topic-name}", kafkaController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/kafka/{topic-name}", kafkaController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/kafka/{topic-name}", kafkaController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/manifest", manifestController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/manifest", manifestController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/manifest/{manifest-name}", manifestController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/manifest/{manifest-name}", manifestController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/manifest/{manifest-name}", manifestController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/connector", connectorController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/connector", connectorController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/connector/{connector-name}", connectorController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/connector/{connector-name}", connectorController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/connector/{connector-name}", connectorController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/schema", schemaController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/schema", schemaController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{subject}", schemaController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{subject}", schemaController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/schema/{subject}", schemaController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/postgres", postgresController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/postgres", postgresController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/postgres/{database-name}", postgresController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/postgres/{database-name}", postgresController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/postgres/{database-name}", postgresController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/dynamodb", dynamodbController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/dynamodb", dynamodbController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/dynamodb/{table-name}", dynamodbController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/dynamodb/{table-name}", dynamodbController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/dynamodb/{table-name}", dynamodbController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/webhook", webhookController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/webhook", webhookController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/webhook/{webhook-name}", webhookController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/webhook/{webhook-name}", webhookController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/webhook/{webhook-name}", webhookController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/airflow", airflowController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/airflow", airflowController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/airflow/{airflow-name}", airflowController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/airflow/{airflow-name}", airflowController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/airflow/{airflow-name}", airflowController.RemoveRelease).Methods(http.MethodDelete)
+
+	router.HandleFunc("/k8s-secret", kubernetesSecretController.Release).Methods(http.MethodPost)
+	router.HandleFunc("/k8s-secret", kubernetesSecretController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/k8s-secret/{secret-name}", kubernetesSecretController.GetReleaseDetail).Methods(http.MethodGet)
+	router.HandleFunc("/k8s-secret/{secret-name}", kubernetesSecretController.UpdateRelease).Methods(http.MethodPut)
+	router.HandleFunc("/k8s-secret/{secret-name}", kubernetesSecretController.RemoveRelease).Methods(http.MethodDelete)
+
 	router.HandleFunc("/module", moduleController.AddModule).Methods(http.MethodPost)
 	router.HandleFunc("/module/release", moduleController.AddModuleRelease).Methods(http.MethodPost)
 	router.HandleFunc("/module/release", moduleController.GetAllReleaseName).Methods(http.MethodGet)
+	router.HandleFunc("/module/release/template", moduleController.TemplateModuleRelease).Methods(http.MethodPost)
 	router.HandleFunc("/module/release/{release-name}", moduleController.GetReleaseDetail).Methods(http.MethodGet)
 	router.HandleFunc("/module/release/{release-name}", moduleController.UpdateModuleRelease).Methods(http.MethodPut)
 	router.HandleFunc("/module/release/{release-name}", moduleController.DeleteModuleRelease).Methods(http.MethodDelete)
+	router.HandleFunc("/module/release/{release-name}/status", moduleController.GetReleaseStatus).Methods(http.MethodGet)
 
 	return router
 }
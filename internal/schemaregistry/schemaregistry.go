@@ -0,0 +1,169 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// ErrNotFound is returned when the subject or its config does not exist.
+var ErrNotFound = errors.New("subject not found")
+
+// Client talks to the REST API of a Confluent-compatible schema registry.
+type Client struct {
+	registryConfig configs.SchemaRegistryConfig
+	httpClient     *http.Client
+}
+
+type Schema struct {
+	Subject    string `json:"subject,omitempty"`
+	ID         int    `json:"id,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema"`
+}
+
+var registryClient *Client
+
+func GetSchemaRegistryClient(registryConfig configs.SchemaRegistryConfig) *Client {
+	if registryClient != nil {
+		return registryClient
+	}
+	registryClient = &Client{}
+	registryClient.registryConfig = registryConfig
+	registryClient.httpClient = &http.Client{
+		Timeout: registryConfig.Timeout,
+	}
+	return registryClient
+}
+
+// Register registers the schema under the subject and returns its id. An
+// already registered schema returns its existing id.
+func (c *Client) Register(ctx context.Context, subject string, schema Schema) (int, error) {
+	result := struct {
+		ID int `json:"id"`
+	}{}
+	err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", schema, &result)
+	return result.ID, err
+}
+
+// Lookup returns the id and version of the schema within the subject.
+func (c *Client) Lookup(ctx context.Context, subject string, schema Schema) (Schema, error) {
+	result := Schema{}
+	err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject), schema, &result)
+	return result, err
+}
+
+// CheckCompatibility tests the schema against the latest version of the
+// subject and returns the reasons it is incompatible, if any.
+func (c *Client) CheckCompatibility(ctx context.Context, subject string, schema Schema) (bool, []string, error) {
+	result := struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}{}
+	err := c.do(ctx, http.MethodPost, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest?verbose=true", schema, &result)
+	return result.IsCompatible, result.Messages, err
+}
+
+func (c *Client) SetCompatibility(ctx context.Context, subject string, compatibility string) error {
+	body := map[string]string{"compatibility": compatibility}
+	return c.do(ctx, http.MethodPut, "/config/"+url.PathEscape(subject), body, nil)
+}
+
+// DeleteCompatibility resets the subject to the global compatibility level.
+func (c *Client) DeleteCompatibility(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, "/config/"+url.PathEscape(subject), nil, nil)
+}
+
+// GetSubjectCompatibility returns the level set on the subject itself, and
+// ErrNotFound when the subject follows the global level.
+func (c *Client) GetSubjectCompatibility(ctx context.Context, subject string) (string, error) {
+	result := struct {
+		CompatibilityLevel string `json:"compatibilityLevel"`
+	}{}
+	err := c.do(ctx, http.MethodGet, "/config/"+url.PathEscape(subject), nil, &result)
+	return result.CompatibilityLevel, err
+}
+
+func (c *Client) GetCompatibility(ctx context.Context, subject string) (string, error) {
+	result := struct {
+		CompatibilityLevel string `json:"compatibilityLevel"`
+	}{}
+	err := c.do(ctx, http.MethodGet, "/config/"+url.PathEscape(subject)+"?defaultToGlobal=true", nil, &result)
+	return result.CompatibilityLevel, err
+}
+
+func (c *Client) GetLatest(ctx context.Context, subject string) (Schema, error) {
+	result := Schema{}
+	err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &result)
+	return result, err
+}
+
+// DeleteSubject soft deletes every version of the subject.
+func (c *Client) DeleteSubject(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, "/subjects/"+url.PathEscape(subject), nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	if c.registryConfig.URL == "" {
+		return errors.New("no schema registry url configured")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.registryConfig.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.registryConfig.Username != "" {
+		req.SetBasicAuth(c.registryConfig.Username, c.registryConfig.Password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if res.StatusCode >= 300 {
+		// errors come as {"error_code": int, "message": string}
+		registryError := struct {
+			Message string `json:"message"`
+		}{}
+		data, _ := ioutil.ReadAll(res.Body)
+		if json.Unmarshal(data, &registryError) == nil && registryError.Message != "" {
+			return fmt.Errorf("schema registry %s %s: %s", method, path, registryError.Message)
+		}
+		return fmt.Errorf("schema registry %s %s: %s", method, path, res.Status)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IAirflowService interface {
+	InstallOrUpgradeAirflow(models.Airflow) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Airflow, error)
+	RemoveAirflow(string) error
+}
+
+type AirflowService struct {
+	airflowProvider repositories.Providers
+}
+
+func InitAirflowService(airflowProvider repositories.Providers) IAirflowService {
+	airflowService := &AirflowService{}
+	airflowService.airflowProvider = airflowProvider
+	return airflowService
+}
+
+func (s *AirflowService) InstallOrUpgradeAirflow(airflowData models.Airflow) error {
+	oldAirflowInterface, err := s.airflowProvider.GetDetail(airflowData.Name)
+	oldAirflow := oldAirflowInterface.(models.Airflow)
+	if err == gorm.ErrRecordNotFound {
+		return s.installAirflow(airflowData)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeAirflow(airflowData, oldAirflow)
+}
+
+func (s *AirflowService) installAirflow(airflowData models.Airflow) error {
+	airflowData.Revision = 1
+	return installComponent(s.airflowProvider, airflowData)
+}
+
+func (s *AirflowService) upgradeAirflow(airflowData models.Airflow, oldAirflow models.Airflow) error {
+	airflowData.Revision = oldAirflow.Revision + 1
+
+	return upgradeComponent(s.airflowProvider, airflowData)
+}
+
+func (s *AirflowService) RemoveAirflow(airflowName string) error {
+	return removeComponent(s.airflowProvider, airflowName)
+}
+
+func (s *AirflowService) GetAllReleaseName() ([]string, error) {
+	result, err := s.airflowProvider.GetAllName()
+	return result, err
+}
+
+func (s *AirflowService) GetReleaseDetail(releaseName string) (models.Airflow, error) {
+	resultInterface, err := s.airflowProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Airflow)
+	return result, err
+}
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"bytes"
+	"errors"
+
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/requests"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
 	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chart/loader"
 )
 
 type IChartService interface {
@@ -11,15 +17,28 @@ type IChartService interface {
 	GetAllReleaseName() ([]string, error)
 	GetReleaseDetail(string) (models.ChartRelease, error)
 	RemoveChart(string) error
+	ImportChart(requests.ImportChart) error
+	RollbackChart(string, int) error
+	GetReleaseHistory(string) ([]responses.ChartHistory, error)
+	TemplateChart(models.ChartRelease, bool) (responses.ChartManifest, error)
+	GetReleaseTestResults(string) ([]models.ChartTestResult, error)
+	GetReleaseStatus(string) (responses.ComponentStatus, error)
+	UploadChartArchive([]byte) (models.ChartArchive, error)
+	GetAllChartArchive() ([]models.ChartArchive, error)
+	RemoveChartArchive(string, string) error
 }
 
 type ChartService struct {
-	chartProvider repositories.Providers
+	chartProvider          repositories.IChartProvider
+	moduleRepository       repositories.IModuleRepository
+	chartArchiveRepository repositories.IChartArchiveRepository
 }
 
-func InitChartService(chartProvider repositories.Providers) IChartService {
+func InitChartService(chartProvider repositories.IChartProvider, moduleRepository repositories.IModuleRepository, chartArchiveRepository repositories.IChartArchiveRepository) IChartService {
 	chartService := &ChartService{}
 	chartService.chartProvider = chartProvider
+	chartService.moduleRepository = moduleRepository
+	chartService.chartArchiveRepository = chartArchiveRepository
 	return chartService
 }
 
@@ -77,3 +96,95 @@ func (h *ChartService) GetReleaseDetail(releaseName string) (models.ChartRelease
 	result := resultInterface.(models.ChartRelease)
 	return result, err
 }
+
+func (h *ChartService) ImportChart(request requests.ImportChart) error {
+	_, err := h.chartProvider.GetDetail(request.ReleaseName)
+	if err == nil {
+		return errors.New("release is already managed")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	chart, err := h.chartProvider.Import(request.ReleaseName, request.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if request.Name != "" {
+		chart.Name = request.Name
+	}
+
+	if request.ModuleRelease != "" {
+		moduleRelease, err := h.moduleRepository.GetModuleRelease(request.ModuleRelease)
+		if err != nil {
+			return err
+		}
+		chart.ModuleReleaseID = moduleRelease.ID
+	}
+
+	return h.chartProvider.Add(chart)
+}
+
+func (h *ChartService) RollbackChart(releaseName string, revision int) error {
+	chartInterface, err := h.chartProvider.GetDetail(releaseName)
+	if err != nil {
+		return err
+	}
+
+	chart, err := h.chartProvider.Rollback(chartInterface.(models.ChartRelease), revision)
+	if err != nil {
+		return err
+	}
+
+	return h.chartProvider.Update(chart)
+}
+
+func (h *ChartService) GetReleaseHistory(releaseName string) ([]responses.ChartHistory, error) {
+	chartInterface, err := h.chartProvider.GetDetail(releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.chartProvider.GetHistory(chartInterface.(models.ChartRelease))
+}
+
+func (h *ChartService) TemplateChart(chart models.ChartRelease, validate bool) (responses.ChartManifest, error) {
+	return h.chartProvider.Template(chart, validate)
+}
+
+func (h *ChartService) GetReleaseTestResults(releaseName string) ([]models.ChartTestResult, error) {
+	return h.chartProvider.GetTestResults(releaseName)
+}
+
+func (h *ChartService) GetReleaseStatus(releaseName string) (responses.ComponentStatus, error) {
+	chart, err := h.chartProvider.GetDetail(releaseName)
+	if err != nil {
+		return responses.ComponentStatus{}, err
+	}
+
+	return h.chartProvider.GetStatus(chart)
+}
+
+func (h *ChartService) UploadChartArchive(content []byte) (models.ChartArchive, error) {
+	chart, err := loader.LoadArchive(bytes.NewReader(content))
+	if err != nil {
+		return models.ChartArchive{}, err
+	}
+
+	archive := models.ChartArchive{
+		Name:    chart.Metadata.Name,
+		Version: chart.Metadata.Version,
+		Content: content,
+	}
+	err = h.chartArchiveRepository.InsertChartArchive(archive)
+	return archive, err
+}
+
+func (h *ChartService) GetAllChartArchive() ([]models.ChartArchive, error) {
+	return h.chartArchiveRepository.GetAllChartArchive()
+}
+
+func (h *ChartService) RemoveChartArchive(name string, version string) error {
+	return h.chartArchiveRepository.DeleteChartArchive(name, version)
+}
@@ -0,0 +1,34 @@
+package services
+
+import "github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+
+// installComponent creates the component and records it.
+func installComponent(provider repositories.Providers, component interface{}) error {
+	err := provider.InstallComponent(component)
+	if err != nil {
+		return err
+	}
+	return provider.Add(component)
+}
+
+// upgradeComponent updates the component and its record.
+func upgradeComponent(provider repositories.Providers, component interface{}) error {
+	err := provider.UpdateComponent(component)
+	if err != nil {
+		return err
+	}
+	return provider.Update(component)
+}
+
+// removeComponent deletes the recorded component of that name and its record.
+func removeComponent(provider repositories.Providers, name string) error {
+	component, err := provider.GetDetail(name)
+	if err != nil {
+		return err
+	}
+	err = provider.UninstallComponent(component)
+	if err != nil {
+		return err
+	}
+	return provider.Remove(component)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IConnectorService interface {
+	InstallOrUpgradeConnector(models.Connector) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Connector, error)
+	RemoveConnector(string) error
+}
+
+type ConnectorService struct {
+	connectorProvider repositories.Providers
+}
+
+func InitConnectorService(connectorProvider repositories.Providers) IConnectorService {
+	connectorService := &ConnectorService{}
+	connectorService.connectorProvider = connectorProvider
+	return connectorService
+}
+
+func (s *ConnectorService) InstallOrUpgradeConnector(connector models.Connector) error {
+	oldConnectorInterface, err := s.connectorProvider.GetDetail(connector.Name)
+	oldConnector := oldConnectorInterface.(models.Connector)
+	if err == gorm.ErrRecordNotFound {
+		return s.installConnector(connector)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeConnector(connector, oldConnector)
+}
+
+func (s *ConnectorService) installConnector(connector models.Connector) error {
+	connector.Revision = 1
+	return installComponent(s.connectorProvider, connector)
+}
+
+func (s *ConnectorService) upgradeConnector(connector models.Connector, oldConnector models.Connector) error {
+	connector.Revision = oldConnector.Revision + 1
+
+	return upgradeComponent(s.connectorProvider, connector)
+}
+
+func (s *ConnectorService) RemoveConnector(connector string) error {
+	return removeComponent(s.connectorProvider, connector)
+}
+
+func (s *ConnectorService) GetAllReleaseName() ([]string, error) {
+	result, err := s.connectorProvider.GetAllName()
+	return result, err
+}
+
+func (s *ConnectorService) GetReleaseDetail(releaseName string) (models.Connector, error) {
+	resultInterface, err := s.connectorProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Connector)
+	return result, err
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IDynamoDBService interface {
+	InstallOrUpgradeTable(models.DynamoDBTable) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.DynamoDBTable, error)
+	RemoveTable(string) error
+}
+
+type DynamoDBService struct {
+	dynamodbProvider repositories.Providers
+}
+
+func InitDynamoDBService(dynamodbProvider repositories.Providers) IDynamoDBService {
+	dynamodbService := &DynamoDBService{}
+	dynamodbService.dynamodbProvider = dynamodbProvider
+	return dynamodbService
+}
+
+func (s *DynamoDBService) InstallOrUpgradeTable(table models.DynamoDBTable) error {
+	if table.DeletionPolicy == "" {
+		table.DeletionPolicy = models.DynamoDBDeletionPolicyRetain
+	}
+
+	oldTableInterface, err := s.dynamodbProvider.GetDetail(table.Name)
+	oldTable := oldTableInterface.(models.DynamoDBTable)
+	if err == gorm.ErrRecordNotFound {
+		return s.installTable(table)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeTable(table, oldTable)
+}
+
+func (s *DynamoDBService) installTable(table models.DynamoDBTable) error {
+	table.Revision = 1
+	return installComponent(s.dynamodbProvider, table)
+}
+
+func (s *DynamoDBService) upgradeTable(table models.DynamoDBTable, oldTable models.DynamoDBTable) error {
+	if table.Account != oldTable.Account || table.Region != oldTable.Region {
+		return errors.New("account and region of a dynamodb table cannot be changed")
+	}
+	table.Revision = oldTable.Revision + 1
+
+	return upgradeComponent(s.dynamodbProvider, table)
+}
+
+func (s *DynamoDBService) RemoveTable(table string) error {
+	return removeComponent(s.dynamodbProvider, table)
+}
+
+func (s *DynamoDBService) GetAllReleaseName() ([]string, error) {
+	result, err := s.dynamodbProvider.GetAllName()
+	return result, err
+}
+
+func (s *DynamoDBService) GetReleaseDetail(releaseName string) (models.DynamoDBTable, error) {
+	resultInterface, err := s.dynamodbProvider.GetDetail(releaseName)
+	result := resultInterface.(models.DynamoDBTable)
+	return result, err
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IFirehoseService interface {
+	InstallOrUpgradeFirehose(models.Firehose) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Firehose, error)
+	RemoveFirehose(string) error
+}
+
+type FirehoseService struct {
+	firehoseProvider repositories.Providers
+}
+
+func InitFirehoseService(firehoseProvider repositories.Providers) IFirehoseService {
+	firehoseService := &FirehoseService{}
+	firehoseService.firehoseProvider = firehoseProvider
+	return firehoseService
+}
+
+func (f *FirehoseService) InstallOrUpgradeFirehose(firehose models.Firehose) error {
+	oldFirehoseInterface, err := f.firehoseProvider.GetDetail(firehose.Name)
+	oldFirehose := oldFirehoseInterface.(models.Firehose)
+	if err == gorm.ErrRecordNotFound {
+		return f.installFirehose(firehose)
+	}
+	if err != nil {
+		return err
+	}
+	return f.upgradeFirehose(firehose, oldFirehose)
+}
+
+func (f *FirehoseService) installFirehose(firehose models.Firehose) error {
+	firehose.Revision = 1
+	return installComponent(f.firehoseProvider, firehose)
+}
+
+func (f *FirehoseService) upgradeFirehose(firehose models.Firehose, oldFirehose models.Firehose) error {
+	if firehose.Account != oldFirehose.Account || firehose.Region != oldFirehose.Region {
+		return errors.New("account and region of a firehose delivery stream cannot be changed")
+	}
+	firehose.Revision = oldFirehose.Revision + 1
+
+	return upgradeComponent(f.firehoseProvider, firehose)
+}
+
+func (f *FirehoseService) RemoveFirehose(firehose string) error {
+	return removeComponent(f.firehoseProvider, firehose)
+}
+
+func (f *FirehoseService) GetAllReleaseName() ([]string, error) {
+	result, err := f.firehoseProvider.GetAllName()
+	return result, err
+}
+
+func (f *FirehoseService) GetReleaseDetail(releaseName string) (models.Firehose, error) {
+	resultInterface, err := f.firehoseProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Firehose)
+	return result, err
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IKafkaService interface {
+	InstallOrUpgradeTopic(models.KafkaTopic) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.KafkaTopic, error)
+	RemoveTopic(string) error
+}
+
+type KafkaService struct {
+	kafkaProvider repositories.Providers
+}
+
+func InitKafkaService(kafkaProvider repositories.Providers) IKafkaService {
+	kafkaService := &KafkaService{}
+	kafkaService.kafkaProvider = kafkaProvider
+	return kafkaService
+}
+
+func (s *KafkaService) InstallOrUpgradeTopic(topic models.KafkaTopic) error {
+	oldTopicInterface, err := s.kafkaProvider.GetDetail(topic.Name)
+	oldTopic := oldTopicInterface.(models.KafkaTopic)
+	if err == gorm.ErrRecordNotFound {
+		return s.installTopic(topic)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeTopic(topic, oldTopic)
+}
+
+func (s *KafkaService) installTopic(topic models.KafkaTopic) error {
+	topic.Revision = 1
+	return installComponent(s.kafkaProvider, topic)
+}
+
+func (s *KafkaService) upgradeTopic(topic models.KafkaTopic, oldTopic models.KafkaTopic) error {
+	if topic.Partitions > 0 && topic.Partitions < oldTopic.Partitions {
+		return fmt.Errorf("partitions of kafka topic %s cannot be decreased from %d to %d", topic.Name, oldTopic.Partitions, topic.Partitions)
+	}
+	topic.Revision = oldTopic.Revision + 1
+
+	return upgradeComponent(s.kafkaProvider, topic)
+}
+
+func (s *KafkaService) RemoveTopic(topic string) error {
+	return removeComponent(s.kafkaProvider, topic)
+}
+
+func (s *KafkaService) GetAllReleaseName() ([]string, error) {
+	result, err := s.kafkaProvider.GetAllName()
+	return result, err
+}
+
+func (s *KafkaService) GetReleaseDetail(releaseName string) (models.KafkaTopic, error) {
+	resultInterface, err := s.kafkaProvider.GetDetail(releaseName)
+	result := resultInterface.(models.KafkaTopic)
+	return result, err
+}
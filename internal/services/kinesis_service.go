@@ -1,7 +1,10 @@
 package services
 
 import (
+	"errors"
+
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/requests"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
 	"gorm.io/gorm"
 )
@@ -9,26 +12,31 @@ import (
 type IKinesisService interface {
 	InstallOrUpgradeKinesis(models.Kinesis) error
 	GetAllReleaseName() ([]string, error)
-	GetReleaseDetail(string) (models.Kinesis, error)
-	RemoveKinesis(string) error
+	GetReleaseDetail(requests.KinesisStream) (models.Kinesis, error)
+	RemoveKinesis(requests.KinesisStream) error
+	ImportKinesis(requests.ImportKinesis) error
 }
 
 type KinesisService struct {
-	kinesisProvider repositories.Providers
+	kinesisProvider  repositories.IKinesisProvider
+	moduleRepository repositories.IModuleRepository
 }
 
-func InitKinesisService(kinesisProvider repositories.Providers) IKinesisService {
+func InitKinesisService(kinesisProvider repositories.IKinesisProvider, moduleRepository repositories.IModuleRepository) IKinesisService {
 	KinesisService := &KinesisService{}
 	KinesisService.kinesisProvider = kinesisProvider
+	KinesisService.moduleRepository = moduleRepository
 	return KinesisService
 }
 
 func (k *KinesisService) InstallOrUpgradeKinesis(kinesis models.Kinesis) error {
-	oldKinesisInterface, err := k.kinesisProvider.GetDetail(kinesis.Name)
-	oldKinesis := oldKinesisInterface.(models.Kinesis)
+	oldKinesis, err := k.kinesisProvider.GetStreamDetail(kinesis.Name, kinesis.Account, kinesis.Region)
 	if err == gorm.ErrRecordNotFound {
 		return k.installKinesis(kinesis)
 	}
+	if err != nil {
+		return err
+	}
 	return k.upgradeKinesis(kinesis, oldKinesis)
 }
 
@@ -54,8 +62,8 @@ func (k *KinesisService) upgradeKinesis(kinesis models.Kinesis, oldKinesis model
 	return err
 }
 
-func (k *KinesisService) RemoveKinesis(kinesis string) error {
-	kinesisInstance, err := k.kinesisProvider.GetDetail(kinesis)
+func (k *KinesisService) RemoveKinesis(stream requests.KinesisStream) error {
+	kinesisInstance, err := k.getKinesis(stream)
 	if err != nil {
 		return err
 	}
@@ -72,8 +80,40 @@ func (k *KinesisService) GetAllReleaseName() ([]string, error) {
 	return result, err
 }
 
-func (k *KinesisService) GetReleaseDetail(releaseName string) (models.Kinesis, error) {
-	resultInterface, err := k.kinesisProvider.GetDetail(releaseName)
+func (k *KinesisService) GetReleaseDetail(stream requests.KinesisStream) (models.Kinesis, error) {
+	return k.getKinesis(stream)
+}
+
+func (k *KinesisService) getKinesis(stream requests.KinesisStream) (models.Kinesis, error) {
+	if stream.Scoped {
+		return k.kinesisProvider.GetStreamDetail(stream.Name, stream.Account, stream.Region)
+	}
+	resultInterface, err := k.kinesisProvider.GetDetail(stream.Name)
 	result := resultInterface.(models.Kinesis)
 	return result, err
 }
+
+func (k *KinesisService) ImportKinesis(request requests.ImportKinesis) error {
+	_, err := k.kinesisProvider.GetStreamDetail(request.Name, request.Account, request.Region)
+	if err == nil {
+		return errors.New("kinesis stream is already managed")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	kinesis, err := k.kinesisProvider.Import(request.Name, request.Account, request.Region)
+	if err != nil {
+		return err
+	}
+
+	if request.ModuleRelease != "" {
+		moduleRelease, err := k.moduleRepository.GetModuleRelease(request.ModuleRelease)
+		if err != nil {
+			return err
+		}
+		kinesis.ModuleReleaseID = moduleRelease.ID
+	}
+
+	return k.kinesisProvider.Add(kinesis)
+}
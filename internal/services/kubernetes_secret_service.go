@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type IKubernetesSecretService interface {
+	InstallOrUpgradeKubernetesSecret(models.KubernetesSecret) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.KubernetesSecret, error)
+	RemoveKubernetesSecret(string) error
+	SyncKubernetesSecrets()
+	StartSync(time.Duration)
+}
+
+type KubernetesSecretService struct {
+	kubernetesSecretProvider repositories.Providers
+}
+
+func InitKubernetesSecretService(kubernetesSecretProvider repositories.Providers) IKubernetesSecretService {
+	kubernetesSecretService := &KubernetesSecretService{}
+	kubernetesSecretService.kubernetesSecretProvider = kubernetesSecretProvider
+	return kubernetesSecretService
+}
+
+func (s *KubernetesSecretService) InstallOrUpgradeKubernetesSecret(secret models.KubernetesSecret) error {
+	// fills in the default namespace and type
+	secretInterface, err := s.kubernetesSecretProvider.Convert(secret)
+	if err != nil {
+		return err
+	}
+	secret = secretInterface.(models.KubernetesSecret)
+
+	oldSecretInterface, err := s.kubernetesSecretProvider.GetDetail(secret.Name)
+	oldSecret := oldSecretInterface.(models.KubernetesSecret)
+	if err == gorm.ErrRecordNotFound {
+		return s.installKubernetesSecret(secret)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeKubernetesSecret(secret, oldSecret)
+}
+
+func (s *KubernetesSecretService) installKubernetesSecret(secret models.KubernetesSecret) error {
+	secret.Revision = 1
+	return installComponent(s.kubernetesSecretProvider, secret)
+}
+
+func (s *KubernetesSecretService) upgradeKubernetesSecret(secret models.KubernetesSecret, oldSecret models.KubernetesSecret) error {
+	secret.Revision = oldSecret.Revision + 1
+
+	return upgradeComponent(s.kubernetesSecretProvider, secret)
+}
+
+func (s *KubernetesSecretService) RemoveKubernetesSecret(secretName string) error {
+	return removeComponent(s.kubernetesSecretProvider, secretName)
+}
+
+func (s *KubernetesSecretService) GetAllReleaseName() ([]string, error) {
+	result, err := s.kubernetesSecretProvider.GetAllName()
+	return result, err
+}
+
+func (s *KubernetesSecretService) GetReleaseDetail(releaseName string) (models.KubernetesSecret, error) {
+	resultInterface, err := s.kubernetesSecretProvider.GetDetail(releaseName)
+	result := resultInterface.(models.KubernetesSecret)
+	return result, err
+}
+
+// SyncKubernetesSecrets applies every stored secret again, so values changed
+// in the secret providers reach the cluster. A failing secret is logged and
+// does not stop the others.
+func (s *KubernetesSecretService) SyncKubernetesSecrets() {
+	names, err := s.kubernetesSecretProvider.GetAllName()
+	if err != nil {
+		log.Error().Err(err).Msg("listing kubernetes secrets to sync")
+		return
+	}
+	for _, name := range names {
+		secret, err := s.kubernetesSecretProvider.GetDetail(name)
+		if err != nil {
+			log.Error().Err(err).Str("secret", name).Msg("reading kubernetes secret to sync")
+			continue
+		}
+		err = s.kubernetesSecretProvider.InstallComponent(secret)
+		if err != nil {
+			log.Error().Err(err).Str("secret", name).Msg("syncing kubernetes secret")
+		}
+	}
+}
+
+// StartSync syncs the secrets every interval until the process exits.
+func (s *KubernetesSecretService) StartSync(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.SyncKubernetesSecrets()
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IManifestService interface {
+	InstallOrUpgradeManifest(models.Manifest) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Manifest, error)
+	RemoveManifest(string) error
+}
+
+type ManifestService struct {
+	manifestProvider repositories.Providers
+}
+
+func InitManifestService(manifestProvider repositories.Providers) IManifestService {
+	manifestService := &ManifestService{}
+	manifestService.manifestProvider = manifestProvider
+	return manifestService
+}
+
+func (s *ManifestService) InstallOrUpgradeManifest(manifest models.Manifest) error {
+	oldManifestInterface, err := s.manifestProvider.GetDetail(manifest.Name)
+	oldManifest := oldManifestInterface.(models.Manifest)
+	if err == gorm.ErrRecordNotFound {
+		return s.installManifest(manifest)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeManifest(manifest, oldManifest)
+}
+
+func (s *ManifestService) installManifest(manifest models.Manifest) error {
+	manifest.Revision = 1
+	return installComponent(s.manifestProvider, manifest)
+}
+
+func (s *ManifestService) upgradeManifest(manifest models.Manifest, oldManifest models.Manifest) error {
+	manifest.Revision = oldManifest.Revision + 1
+
+	return upgradeComponent(s.manifestProvider, manifest)
+}
+
+func (s *ManifestService) RemoveManifest(manifest string) error {
+	return removeComponent(s.manifestProvider, manifest)
+}
+
+func (s *ManifestService) GetAllReleaseName() ([]string, error) {
+	result, err := s.manifestProvider.GetAllName()
+	return result, err
+}
+
+func (s *ManifestService) GetReleaseDetail(releaseName string) (models.Manifest, error) {
+	resultInterface, err := s.manifestProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Manifest)
+	return result, err
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+)
+
+// outputProbes are the values the outputs that are not available yet are
+// rendered with. A section rendering the same with both does not depend on
+// them, whatever the template does with the values.
+var outputProbes = [2]string{"", "1"}
+
+// renderReady renders the spec with the outputs available so far. When some
+// are missing the spec is rendered once with each probe, and the handlers
+// whose section differs between the two renders are returned as waiting,
+// along with the missing outputs.
+func (m ModuleService) renderReady(module models.Module, values models.ModuleTemplate, outputs map[string]map[string]string) (map[string][]interface{}, map[string]bool, []string, error) {
+	missing := map[string]bool{}
+	var renders [2]map[string][]interface{}
+	for i, probe := range outputProbes {
+		probe := probe
+		var err error
+		renders[i], err = m.renderValues(module, values, func(handler string, key string) (string, error) {
+			if value, ok := outputs[handler][key]; ok {
+				return value, nil
+			}
+			missing[key+" of "+handler] = true
+			return probe, nil
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(missing) == 0 {
+			return renders[0], map[string]bool{}, nil, nil
+		}
+	}
+
+	waiting := map[string]bool{}
+	for _, render := range renders {
+		for handler := range render {
+			if !reflect.DeepEqual(renders[0][handler], renders[1][handler]) {
+				waiting[handler] = true
+			}
+		}
+	}
+	var missingList []string
+	for output := range missing {
+		missingList = append(missingList, output)
+	}
+	sort.Strings(missingList)
+	return renders[0], waiting, missingList, nil
+}
+
+// nextHandler picks the pending handler to release next among those not
+// waiting for outputs, the handlers producing outputs first, or returns ""
+// when every pending handler is waiting.
+func nextHandler(pending map[string]bool, waiting map[string]bool, producesOutputs func(string) bool) string {
+	var ready []string
+	for handler := range pending {
+		if !waiting[handler] {
+			ready = append(ready, handler)
+		}
+	}
+	if len(ready) == 0 {
+		return ""
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		if producesOutputs(ready[i]) != producesOutputs(ready[j]) {
+			return producesOutputs(ready[i])
+		}
+		return ready[i] < ready[j]
+	})
+	return ready[0]
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+)
+
+// convertOnlyProvider keeps the components as they are rendered.
+type convertOnlyProvider struct {
+	repositories.Providers
+}
+
+func (convertOnlyProvider) Convert(rawData interface{}) (interface{}, error) {
+	return rawData, nil
+}
+
+func TestRenderReady(t *testing.T) {
+	service := ModuleService{
+		providers: map[string]repositories.Providers{
+			"postgres": convertOnlyProvider{},
+			"sqs":      convertOnlyProvider{},
+			"chart":    convertOnlyProvider{},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		spec        string
+		outputs     map[string]map[string]string
+		wantWaiting map[string]bool
+		wantMissing []string
+	}{
+		{
+			name:        "no outputs",
+			spec:        "chart:\n- name: {{ .Release }}\n",
+			wantWaiting: map[string]bool{},
+		},
+		{
+			name:        "output in a pipeline",
+			spec:        "sqs:\n- name: q\nchart:\n- values:\n    host: {{ output \"postgres\" \"db/host\" | b64enc }}\n",
+			wantWaiting: map[string]bool{"chart": true},
+			wantMissing: []string{"db/host of postgres"},
+		},
+		{
+			name:        "flow style section",
+			spec:        "{chart: [{values: {host: \"{{ output \"postgres\" \"db/host\" }}\"}}], sqs: [{name: q}]}\n",
+			wantWaiting: map[string]bool{"chart": true},
+			wantMissing: []string{"db/host of postgres"},
+		},
+		{
+			name:        "handler that is not a constant",
+			spec:        "{{ $handler := \"postgres\" }}chart:\n- host: {{ output $handler \"db/host\" }}\n",
+			wantWaiting: map[string]bool{"chart": true},
+			wantMissing: []string{"db/host of postgres"},
+		},
+		{
+			name:        "output that is available",
+			spec:        "postgres:\n- name: db\nchart:\n- host: {{ output \"postgres\" \"db/host\" }}\n",
+			outputs:     map[string]map[string]string{"postgres": {"db/host": "localhost"}},
+			wantWaiting: map[string]bool{},
+		},
+		{
+			name:        "output of a handler that is not in the spec",
+			spec:        "chart:\n- host: {{ output \"postgres\" \"db/host\" }}\n",
+			wantWaiting: map[string]bool{"chart": true},
+			wantMissing: []string{"db/host of postgres"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			module := models.Module{Name: "module", Spec: test.spec}
+			values := models.ModuleTemplate{Module: "module", Release: "release"}
+
+			_, waiting, missing, err := service.renderReady(module, values, test.outputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(waiting, test.wantWaiting) {
+				t.Errorf("got waiting %v, want %v", waiting, test.wantWaiting)
+			}
+			if !reflect.DeepEqual(missing, test.wantMissing) {
+				t.Errorf("got missing %v, want %v", missing, test.wantMissing)
+			}
+		})
+	}
+}
+
+func TestNextHandler(t *testing.T) {
+	producesOutputs := func(handler string) bool {
+		return handler == "postgres" || handler == "sqs"
+	}
+
+	tests := []struct {
+		name    string
+		pending []string
+		waiting map[string]bool
+		want    string
+	}{
+		{
+			name:    "producers first",
+			pending: []string{"chart", "sqs", "postgres"},
+			want:    "postgres",
+		},
+		{
+			name:    "waiting handlers are skipped",
+			pending: []string{"chart", "postgres"},
+			waiting: map[string]bool{"postgres": true},
+			want:    "chart",
+		},
+		{
+			name:    "every handler waiting",
+			pending: []string{"chart", "postgres"},
+			waiting: map[string]bool{"chart": true, "postgres": true},
+			want:    "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pending := map[string]bool{}
+			for _, handler := range test.pending {
+				pending[handler] = true
+			}
+
+			got := nextHandler(pending, test.waiting, producesOutputs)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package services
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models/responses"
 	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
 	"sigs.k8s.io/yaml"
 )
@@ -17,6 +20,8 @@ type IModuleService interface {
 	DeleteModuleRelease(models.ModuleRelease) error
 	GetAllReleaseName() ([]string, error)
 	GetReleaseDetail(releaseName string) (models.ModuleRelease, error)
+	TemplateModuleRelease(models.Module, models.ModuleRelease, bool) ([]responses.ChartManifest, error)
+	GetReleaseStatus(string) (responses.ModuleReleaseStatus, error)
 }
 
 type ModuleService struct {
@@ -52,13 +57,7 @@ func (m ModuleService) ReleaseModule(module models.Module, release models.Module
 	release.ModuleName = module.Name
 	release.Revision = 1
 
-	finalSpec, err := m.applyChartTemplate(module, module.Spec, release)
-	if err != nil {
-		return err
-	}
-
-	var spec map[string][]interface{}
-	err = yaml.Unmarshal([]byte(finalSpec), &spec)
+	spec, err := m.renderSpec(module, release)
 	if err != nil {
 		return err
 	}
@@ -68,49 +67,7 @@ func (m ModuleService) ReleaseModule(module models.Module, release models.Module
 		return err
 	}
 
-	for handler := range spec {
-		if _, ok := m.providers[handler]; !ok {
-			err := errors.New("component handler not implemented")
-			return err
-		}
-	}
-
-	for handler, components := range spec {
-		for i, component := range components {
-			spec[handler][i], err = m.providers[handler].Convert(component)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	for handler, components := range spec {
-		for i := range components {
-			spec[handler][i], err = m.providers[handler].PreProcess(spec[handler][i], nil, module, release)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	for handler, components := range spec {
-		for _, component := range components {
-			err = m.providers[handler].InstallComponent(component)
-			if err != nil {
-				if deleteOnFail {
-					m.providers[handler].UninstallComponent(component)
-					m.forceDelete(release)
-				}
-				return err
-			}
-			err = m.providers[handler].Add(component)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return m.releaseSpec(module, release, spec, false, deleteOnFail)
 }
 
 func (m ModuleService) UpdateModuleRelease(module models.Module, release models.ModuleRelease, deleteOnFail bool) error {
@@ -128,67 +85,116 @@ func (m ModuleService) UpdateModuleRelease(module models.Module, release models.
 	release.ModuleName = module.Name
 	release.Revision = oldRelease.Revision + 1
 
-	finalSpec, err := m.applyChartTemplate(module, module.Spec, release)
+	spec, err := m.renderSpec(module, release)
 	if err != nil {
 		return err
 	}
 
-	var spec map[string][]interface{}
-	err = yaml.Unmarshal([]byte(finalSpec), &spec)
+	release, err = m.moduleRepository.InsertModuleRelease(release)
 	if err != nil {
 		return err
 	}
 
-	release, err = m.moduleRepository.InsertModuleRelease(release)
+	err = m.moduleRepository.DeleteModuleRelease(oldRelease)
 	if err != nil {
 		return err
 	}
 
-	err = m.moduleRepository.DeleteModuleRelease(oldRelease)
+	return m.releaseSpec(module, release, spec, true, deleteOnFail)
+}
+
+// releaseSpec installs, or updates when upgrade is set, every component of the
+// spec. Handlers go one at a time, the spec being rendered again with the
+// outputs of the handlers released so far, and a handler is only released once
+// its section does not change with the outputs that are still missing.
+func (m ModuleService) releaseSpec(module models.Module, release models.ModuleRelease, spec map[string][]interface{}, upgrade bool, deleteOnFail bool) error {
+	values, err := m.templateValues(module, release)
 	if err != nil {
 		return err
 	}
 
+	pending := map[string]bool{}
 	for handler := range spec {
-		if _, ok := m.providers[handler]; !ok {
-			err := errors.New("component handler not implemented")
+		pending[handler] = true
+	}
+	producesOutputs := func(handler string) bool {
+		_, ok := m.providers[handler].(repositories.OutputProviders)
+		return ok
+	}
+
+	for len(pending) > 0 {
+		outputs, err := m.getOutputs(release)
+		if err != nil {
+			return err
+		}
+		rendered, waiting, missing, err := m.renderReady(module, values, outputs)
+		if err != nil {
+			if deleteOnFail {
+				m.forceDelete(release)
+			}
 			return err
 		}
-	}
 
-	for handler, components := range spec {
-		for i, component := range components {
-			spec[handler][i], err = m.providers[handler].Convert(component)
-			if err != nil {
-				return err
+		next := nextHandler(pending, waiting, producesOutputs)
+		if next == "" {
+			if deleteOnFail {
+				m.forceDelete(release)
 			}
+			return fmt.Errorf("outputs %s are not available", strings.Join(missing, ", "))
+		}
+		spec[next] = rendered[next]
+
+		err = m.releaseComponents(module, release, spec, []string{next}, upgrade, deleteOnFail)
+		if err != nil {
+			return err
 		}
+		delete(pending, next)
 	}
 
-	for handler, components := range spec {
-		for i := range components {
-			oldChart, err := m.providers[handler].GetDetailFromComponent(spec[handler][i])
-			if err != nil {
-				return err
+	return nil
+}
+
+func (m ModuleService) releaseComponents(module models.Module, release models.ModuleRelease, spec map[string][]interface{}, handlers []string, upgrade bool, deleteOnFail bool) error {
+	var err error
+	for _, handler := range handlers {
+		for i := range spec[handler] {
+			var oldComponent interface{}
+			if upgrade {
+				oldComponent, err = m.providers[handler].GetDetailFromComponent(spec[handler][i])
+				if err != nil {
+					return err
+				}
 			}
 
-			spec[handler][i], err = m.providers[handler].PreProcess(spec[handler][i], oldChart, module, release)
+			spec[handler][i], err = m.providers[handler].PreProcess(spec[handler][i], oldComponent, module, release)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	for handler, components := range spec {
-		for _, component := range components {
-			err = m.providers[handler].UpdateComponent(component)
+	for _, handler := range handlers {
+		for _, component := range spec[handler] {
+			if upgrade {
+				err = m.providers[handler].UpdateComponent(component)
+			} else {
+				err = m.providers[handler].InstallComponent(component)
+			}
 			if err != nil {
 				if deleteOnFail {
+					if !upgrade && !errors.Is(err, repositories.ErrComponentExists) {
+						m.providers[handler].UninstallComponent(component)
+					}
 					m.forceDelete(release)
 				}
 				return err
 			}
-			err = m.providers[handler].Update(component)
+
+			if upgrade {
+				err = m.providers[handler].Update(component)
+			} else {
+				err = m.providers[handler].Add(component)
+			}
 			if err != nil {
 				return err
 			}
@@ -198,6 +204,106 @@ func (m ModuleService) UpdateModuleRelease(module models.Module, release models.
 	return nil
 }
 
+// getOutputs collects the outputs of the release components, keyed by handler.
+func (m ModuleService) getOutputs(release models.ModuleRelease) (map[string]map[string]string, error) {
+	outputs := map[string]map[string]string{}
+	for handlerName, handler := range m.providers {
+		outputProvider, ok := handler.(repositories.OutputProviders)
+		if !ok {
+			continue
+		}
+
+		components, err := handler.GetFromModuleReleaseID(release.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs[handlerName] = map[string]string{}
+		for _, component := range components {
+			componentOutputs, err := outputProvider.GetOutputs(component)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range componentOutputs {
+				outputs[handlerName][key] = value
+			}
+		}
+	}
+	return outputs, nil
+}
+
+func (m ModuleService) TemplateModuleRelease(module models.Module, release models.ModuleRelease, validate bool) ([]responses.ChartManifest, error) {
+	module, err := m.moduleRepository.GetModule(module.Name, module.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	release.ModuleID = module.ID
+	release.ModuleName = module.Name
+
+	spec, err := m.renderSpec(module, release)
+	if err != nil {
+		return nil, err
+	}
+
+	charts := spec["chart"]
+	if len(charts) == 0 {
+		return []responses.ChartManifest{}, nil
+	}
+
+	chartProvider, ok := m.providers["chart"].(repositories.IChartProvider)
+	if !ok {
+		err := errors.New("chart handler does not support templating")
+		return nil, err
+	}
+
+	manifests := make([]responses.ChartManifest, len(charts))
+	for i, component := range charts {
+		manifests[i], err = chartProvider.Template(component.(models.ChartRelease), validate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
+}
+
+func (m ModuleService) GetReleaseStatus(releaseName string) (responses.ModuleReleaseStatus, error) {
+	release, err := m.moduleRepository.GetModuleRelease(releaseName)
+	if err != nil {
+		return responses.ModuleReleaseStatus{}, err
+	}
+
+	status := responses.ModuleReleaseStatus{
+		Name:       release.Name,
+		Module:     release.ModuleName,
+		Version:    release.Version,
+		Revision:   release.Revision,
+		Healthy:    true,
+		Components: map[string][]responses.ComponentStatus{},
+	}
+
+	for handlerName, handler := range m.providers {
+		components, err := handler.GetFromModuleReleaseID(release.ID)
+		if err != nil {
+			return status, err
+		}
+		for _, component := range components {
+			componentStatus, err := handler.GetStatus(component)
+			if err != nil {
+				componentStatus.Healthy = false
+				componentStatus.Status = "unknown"
+				componentStatus.Message = err.Error()
+			}
+			if !componentStatus.Healthy {
+				status.Healthy = false
+			}
+			status.Components[handlerName] = append(status.Components[handlerName], componentStatus)
+		}
+	}
+
+	return status, nil
+}
+
 func (h *ModuleService) GetAllReleaseName() ([]string, error) {
 	return h.moduleRepository.GetAllModuleRelease()
 }
@@ -206,7 +312,65 @@ func (h *ModuleService) GetReleaseDetail(releaseName string) (models.ModuleRelea
 	return h.moduleRepository.GetModuleRelease(releaseName)
 }
 
-func (h *ModuleService) applyChartTemplate(chart models.Module, chartTemplate string, release models.ModuleRelease) (string, error) {
+// renderSpec applies the release values to the module spec and converts every
+// component with its handler. The output template function renders empty
+// strings, so the spec can be validated before anything runs; releaseSpec
+// renders it again with the actual outputs.
+func (m ModuleService) renderSpec(module models.Module, release models.ModuleRelease) (map[string][]interface{}, error) {
+	values, err := m.templateValues(module, release)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.renderValues(module, values, func(handler string, key string) (string, error) {
+		return "", nil
+	})
+}
+
+// renderValues executes the module spec with the template values and the
+// given output function and converts every component with its handler.
+func (m ModuleService) renderValues(module models.Module, values models.ModuleTemplate, output func(string, string) (string, error)) (map[string][]interface{}, error) {
+	buf := new(bytes.Buffer)
+	functions := funcMap()
+	functions["output"] = output
+
+	tmpl, err := template.New("template").Funcs(functions).Parse(module.Spec)
+	if err != nil {
+		return nil, err
+	}
+	err = tmpl.Execute(buf, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec map[string][]interface{}
+	err = yaml.Unmarshal(buf.Bytes(), &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for handler := range spec {
+		if _, ok := m.providers[handler]; !ok {
+			err := errors.New("component handler not implemented")
+			return nil, err
+		}
+	}
+
+	for handler, components := range spec {
+		for i, component := range components {
+			spec[handler][i], err = m.providers[handler].Convert(component)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return spec, nil
+}
+
+// templateValues builds the values the module spec is rendered with, reading
+// the secrets of the release from their providers.
+func (h *ModuleService) templateValues(chart models.Module, release models.ModuleRelease) (models.ModuleTemplate, error) {
 	templateVal := models.ModuleTemplate{
 		Module:  chart.Name,
 		Version: chart.Version,
@@ -214,7 +378,7 @@ func (h *ModuleService) applyChartTemplate(chart models.Module, chartTemplate st
 	}
 	err := yaml.Unmarshal([]byte(release.Values), &templateVal.Values)
 	if err != nil {
-		return "", err
+		return templateVal, err
 	}
 
 	if secret, ok := templateVal.Values["secret"]; ok {
@@ -222,32 +386,22 @@ func (h *ModuleService) applyChartTemplate(chart models.Module, chartTemplate st
 		mappedSecret := secret.(map[string]interface{})
 		for secretProviderName, rawSecret := range mappedSecret {
 			if _, ok := h.secretProviders[secretProviderName]; !ok {
-				err := errors.New("component handler not implemented")
-				return "", err
+				err := fmt.Errorf("secret provider %s is not configured", secretProviderName)
+				return templateVal, err
 			}
 
 			secretProvider := h.secretProviders[secretProviderName]
 			parsedRawSecret := rawSecret.(map[string]interface{})
 			parsedSecret[secretProviderName], err = h.getSecret(parsedRawSecret, secretProvider)
 			if err != nil {
-				return "", err
+				return templateVal, err
 			}
 
 			templateVal.Values["secret"] = parsedSecret
 		}
 	}
 
-	buf := new(bytes.Buffer)
-	tmpl, err := template.New("template").Funcs(funcMap()).Parse(chartTemplate)
-	if err != nil {
-		return "", err
-	}
-	err = tmpl.Execute(buf, templateVal)
-	if err != nil {
-		return "", err
-	}
-	applied := buf.String()
-	return applied, nil
+	return templateVal, nil
 }
 
 func (m ModuleService) getSecret(secretList map[string]interface{}, secretProvider repositories.SecretProviders) (map[string]interface{}, error) {
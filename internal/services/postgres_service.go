@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IPostgresService interface {
+	InstallOrUpgradeDatabase(models.PostgresDatabase) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.PostgresDatabase, error)
+	RemoveDatabase(string) error
+}
+
+type PostgresService struct {
+	postgresProvider repositories.Providers
+}
+
+func InitPostgresService(postgresProvider repositories.Providers) IPostgresService {
+	postgresService := &PostgresService{}
+	postgresService.postgresProvider = postgresProvider
+	return postgresService
+}
+
+func (s *PostgresService) InstallOrUpgradeDatabase(database models.PostgresDatabase) error {
+	if database.DeletionPolicy == "" {
+		database.DeletionPolicy = models.PostgresDeletionPolicyRetain
+	}
+	if database.Role == "" {
+		database.Role = database.Name
+	}
+
+	oldDatabaseInterface, err := s.postgresProvider.GetDetail(database.Name)
+	oldDatabase := oldDatabaseInterface.(models.PostgresDatabase)
+	if err == gorm.ErrRecordNotFound {
+		return s.installDatabase(database)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeDatabase(database, oldDatabase)
+}
+
+func (s *PostgresService) installDatabase(database models.PostgresDatabase) error {
+	database.Revision = 1
+	return installComponent(s.postgresProvider, database)
+}
+
+func (s *PostgresService) upgradeDatabase(database models.PostgresDatabase, oldDatabase models.PostgresDatabase) error {
+	if database.Role != oldDatabase.Role {
+		return errors.New("role of a postgres database cannot be changed")
+	}
+	database.Revision = oldDatabase.Revision + 1
+
+	return upgradeComponent(s.postgresProvider, database)
+}
+
+func (s *PostgresService) RemoveDatabase(database string) error {
+	return removeComponent(s.postgresProvider, database)
+}
+
+func (s *PostgresService) GetAllReleaseName() ([]string, error) {
+	result, err := s.postgresProvider.GetAllName()
+	return result, err
+}
+
+func (s *PostgresService) GetReleaseDetail(releaseName string) (models.PostgresDatabase, error) {
+	resultInterface, err := s.postgresProvider.GetDetail(releaseName)
+	result := resultInterface.(models.PostgresDatabase)
+	return result, err
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IS3Service interface {
+	InstallOrUpgradeBucket(models.S3Bucket) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.S3Bucket, error)
+	RemoveBucket(string) error
+}
+
+type S3Service struct {
+	s3Provider repositories.Providers
+}
+
+func InitS3Service(s3Provider repositories.Providers) IS3Service {
+	s3Service := &S3Service{}
+	s3Service.s3Provider = s3Provider
+	return s3Service
+}
+
+func (s *S3Service) InstallOrUpgradeBucket(bucket models.S3Bucket) error {
+	if bucket.DeletionPolicy == "" {
+		bucket.DeletionPolicy = models.S3DeletionPolicyRetain
+	}
+
+	oldBucketInterface, err := s.s3Provider.GetDetail(bucket.Name)
+	oldBucket := oldBucketInterface.(models.S3Bucket)
+	if err == gorm.ErrRecordNotFound {
+		return s.installBucket(bucket)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeBucket(bucket, oldBucket)
+}
+
+func (s *S3Service) installBucket(bucket models.S3Bucket) error {
+	bucket.Revision = 1
+	return installComponent(s.s3Provider, bucket)
+}
+
+func (s *S3Service) upgradeBucket(bucket models.S3Bucket, oldBucket models.S3Bucket) error {
+	if bucket.Account != oldBucket.Account || bucket.Region != oldBucket.Region {
+		return errors.New("account and region of an s3 bucket cannot be changed")
+	}
+	bucket.Revision = oldBucket.Revision + 1
+
+	return upgradeComponent(s.s3Provider, bucket)
+}
+
+func (s *S3Service) RemoveBucket(bucket string) error {
+	return removeComponent(s.s3Provider, bucket)
+}
+
+func (s *S3Service) GetAllReleaseName() ([]string, error) {
+	result, err := s.s3Provider.GetAllName()
+	return result, err
+}
+
+func (s *S3Service) GetReleaseDetail(releaseName string) (models.S3Bucket, error) {
+	resultInterface, err := s.s3Provider.GetDetail(releaseName)
+	result := resultInterface.(models.S3Bucket)
+	return result, err
+}
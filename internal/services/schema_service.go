@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type ISchemaService interface {
+	InstallOrUpgradeSchema(models.Schema) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Schema, error)
+	RemoveSchema(string) error
+}
+
+type SchemaService struct {
+	schemaProvider repositories.Providers
+}
+
+func InitSchemaService(schemaProvider repositories.Providers) ISchemaService {
+	schemaService := &SchemaService{}
+	schemaService.schemaProvider = schemaProvider
+	return schemaService
+}
+
+func (s *SchemaService) InstallOrUpgradeSchema(schema models.Schema) error {
+	oldSchemaInterface, err := s.schemaProvider.GetDetail(schema.Name)
+	oldSchema := oldSchemaInterface.(models.Schema)
+	if err == gorm.ErrRecordNotFound {
+		return s.installSchema(schema)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeSchema(schema, oldSchema)
+}
+
+func (s *SchemaService) installSchema(schema models.Schema) error {
+	schema.Revision = 1
+	return installComponent(s.schemaProvider, schema)
+}
+
+func (s *SchemaService) upgradeSchema(schema models.Schema, oldSchema models.Schema) error {
+	schema.Revision = oldSchema.Revision + 1
+
+	return upgradeComponent(s.schemaProvider, schema)
+}
+
+func (s *SchemaService) RemoveSchema(schema string) error {
+	return removeComponent(s.schemaProvider, schema)
+}
+
+func (s *SchemaService) GetAllReleaseName() ([]string, error) {
+	result, err := s.schemaProvider.GetAllName()
+	return result, err
+}
+
+func (s *SchemaService) GetReleaseDetail(releaseName string) (models.Schema, error) {
+	resultInterface, err := s.schemaProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Schema)
+	return result, err
+}
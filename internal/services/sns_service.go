@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type ISNSService interface {
+	InstallOrUpgradeTopic(models.SNSTopic) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.SNSTopic, error)
+	RemoveTopic(string) error
+}
+
+type SNSService struct {
+	snsProvider repositories.Providers
+}
+
+func InitSNSService(snsProvider repositories.Providers) ISNSService {
+	snsService := &SNSService{}
+	snsService.snsProvider = snsProvider
+	return snsService
+}
+
+func (s *SNSService) InstallOrUpgradeTopic(topic models.SNSTopic) error {
+	oldTopicInterface, err := s.snsProvider.GetDetail(topic.Name)
+	oldTopic := oldTopicInterface.(models.SNSTopic)
+	if err == gorm.ErrRecordNotFound {
+		return s.installTopic(topic)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeTopic(topic, oldTopic)
+}
+
+func (s *SNSService) installTopic(topic models.SNSTopic) error {
+	topic.Revision = 1
+	return installComponent(s.snsProvider, topic)
+}
+
+func (s *SNSService) upgradeTopic(topic models.SNSTopic, oldTopic models.SNSTopic) error {
+	if topic.Account != oldTopic.Account || topic.Region != oldTopic.Region {
+		return errors.New("account and region of an sns topic cannot be changed")
+	}
+	if topic.Fifo != oldTopic.Fifo {
+		return errors.New("fifo setting of an sns topic cannot be changed")
+	}
+	topic.Revision = oldTopic.Revision + 1
+
+	return upgradeComponent(s.snsProvider, topic)
+}
+
+func (s *SNSService) RemoveTopic(topic string) error {
+	return removeComponent(s.snsProvider, topic)
+}
+
+func (s *SNSService) GetAllReleaseName() ([]string, error) {
+	result, err := s.snsProvider.GetAllName()
+	return result, err
+}
+
+func (s *SNSService) GetReleaseDetail(releaseName string) (models.SNSTopic, error) {
+	resultInterface, err := s.snsProvider.GetDetail(releaseName)
+	result := resultInterface.(models.SNSTopic)
+	return result, err
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type ISQSService interface {
+	InstallOrUpgradeQueue(models.SQSQueue) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.SQSQueue, error)
+	RemoveQueue(string) error
+}
+
+type SQSService struct {
+	sqsProvider repositories.Providers
+}
+
+func InitSQSService(sqsProvider repositories.Providers) ISQSService {
+	sqsService := &SQSService{}
+	sqsService.sqsProvider = sqsProvider
+	return sqsService
+}
+
+func (s *SQSService) InstallOrUpgradeQueue(queue models.SQSQueue) error {
+	oldQueueInterface, err := s.sqsProvider.GetDetail(queue.Name)
+	oldQueue := oldQueueInterface.(models.SQSQueue)
+	if err == gorm.ErrRecordNotFound {
+		return s.installQueue(queue)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeQueue(queue, oldQueue)
+}
+
+func (s *SQSService) installQueue(queue models.SQSQueue) error {
+	queue.Revision = 1
+	return installComponent(s.sqsProvider, queue)
+}
+
+func (s *SQSService) upgradeQueue(queue models.SQSQueue, oldQueue models.SQSQueue) error {
+	if queue.Account != oldQueue.Account || queue.Region != oldQueue.Region {
+		return errors.New("account and region of an sqs queue cannot be changed")
+	}
+	queue.Revision = oldQueue.Revision + 1
+
+	return upgradeComponent(s.sqsProvider, queue)
+}
+
+func (s *SQSService) RemoveQueue(queue string) error {
+	return removeComponent(s.sqsProvider, queue)
+}
+
+func (s *SQSService) GetAllReleaseName() ([]string, error) {
+	result, err := s.sqsProvider.GetAllName()
+	return result, err
+}
+
+func (s *SQSService) GetReleaseDetail(releaseName string) (models.SQSQueue, error) {
+	resultInterface, err := s.sqsProvider.GetDetail(releaseName)
+	result := resultInterface.(models.SQSQueue)
+	return result, err
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/models"
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type IWebhookService interface {
+	InstallOrUpgradeWebhook(models.Webhook) error
+	GetAllReleaseName() ([]string, error)
+	GetReleaseDetail(string) (models.Webhook, error)
+	RemoveWebhook(string) error
+}
+
+type WebhookService struct {
+	webhookProvider repositories.Providers
+}
+
+func InitWebhookService(webhookProvider repositories.Providers) IWebhookService {
+	webhookService := &WebhookService{}
+	webhookService.webhookProvider = webhookProvider
+	return webhookService
+}
+
+func (s *WebhookService) InstallOrUpgradeWebhook(webhook models.Webhook) error {
+	oldWebhookInterface, err := s.webhookProvider.GetDetail(webhook.Name)
+	oldWebhook := oldWebhookInterface.(models.Webhook)
+	if err == gorm.ErrRecordNotFound {
+		return s.installWebhook(webhook)
+	}
+	if err != nil {
+		return err
+	}
+	return s.upgradeWebhook(webhook, oldWebhook)
+}
+
+func (s *WebhookService) installWebhook(webhook models.Webhook) error {
+	webhook.Revision = 1
+	return installComponent(s.webhookProvider, webhook)
+}
+
+func (s *WebhookService) upgradeWebhook(webhook models.Webhook, oldWebhook models.Webhook) error {
+	webhook.Revision = oldWebhook.Revision + 1
+
+	return upgradeComponent(s.webhookProvider, webhook)
+}
+
+func (s *WebhookService) RemoveWebhook(webhook string) error {
+	return removeComponent(s.webhookProvider, webhook)
+}
+
+func (s *WebhookService) GetAllReleaseName() ([]string, error) {
+	result, err := s.webhookProvider.GetAllName()
+	return result, err
+}
+
+func (s *WebhookService) GetReleaseDetail(releaseName string) (models.Webhook, error) {
+	resultInterface, err := s.webhookProvider.GetDetail(releaseName)
+	result := resultInterface.(models.Webhook)
+	return result, err
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gudangada/data-warehouse/warehouse-controller/internal/configs"
+)
+
+// Client makes the HTTP calls declared by webhook components.
+type Client struct {
+	webhookConfig configs.WebhookConfig
+	httpClient    *http.Client
+}
+
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+var webhookClient *Client
+
+func GetWebhookClient(webhookConfig configs.WebhookConfig) *Client {
+	if webhookClient != nil {
+		return webhookClient
+	}
+	webhookClient = &Client{}
+	webhookClient.webhookConfig = webhookConfig
+	webhookClient.httpClient = &http.Client{
+		Timeout: webhookConfig.Timeout,
+	}
+	return webhookClient
+}
+
+// Do makes a single call and returns the response whatever its status, only
+// failing when no response was received.
+func (c *Client) Do(ctx context.Context, request Request) (Response, error) {
+	var reader io.Reader
+	if request.Body != "" {
+		reader = strings.NewReader(request.Body)
+	}
+
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, request.URL, reader)
+	if err != nil {
+		return Response{}, err
+	}
+	if request.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return Response{}, err
+	}
+	response := Response{
+		StatusCode: res.StatusCode,
+		Body:       data,
+	}
+	return response, nil
+}